@@ -0,0 +1,304 @@
+package gotable
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AggFunc computes an aggregate value, e.g. a total, of a list of cells.
+// Cells which don't fit the aggregate, e.g. strings for a sum, are ignored.
+// If no cell fits, e.g. for an empty range of rows, sum and count are zeros,
+// a sum of a column is typed from the column, CELLFLOAT for a CELLFLOAT column
+// and CELLINT otherwise. Min, max, avg, percentile and stddev have no value
+// then, they are an empty cell, see Cell.HasValue. Implement it to add your
+// own aggregates, e.g. a weighted average.
+type AggFunc interface {
+	Name() string                // unique name of the aggregate, e.g. "sum"
+	Aggregate(cells []Cell) Cell // aggregate value of the cells
+}
+
+// aggFunc implements AggFunc with a plain function
+type aggFunc struct {
+	name string
+	f    func(cells []Cell) Cell
+}
+
+func (a aggFunc) Name() string                { return a.name }
+func (a aggFunc) Aggregate(cells []Cell) Cell { return a.f(cells) }
+
+// AggSum et. al. are the built-in aggregates
+var (
+	AggSum   AggFunc = aggFunc{"sum", aggSum}     // sum of numbers, float if any of them is float
+	AggAvg   AggFunc = aggFunc{"avg", aggAvg}     // average of numbers as float
+	AggMin   AggFunc = aggFunc{"min", aggMin}     // smallest number or earliest date
+	AggMax   AggFunc = aggFunc{"max", aggMax}     // largest number or latest date
+	AggCount AggFunc = aggFunc{"count", aggCount} // number of cells holding a value
+
+	AggCountDistinct AggFunc = aggFunc{"countdistinct", aggCountDistinct} // number of distinct values
+	AggMedian        AggFunc = AggPercentile(50)                          // middle number as float
+	AggStdDev        AggFunc = aggFunc{"stddev", aggStdDev}               // sample standard deviation of numbers as float
+)
+
+// aggFuncs holds the aggregates by name, used to restore them from json
+var aggFuncs = struct {
+	sync.RWMutex
+	m map[string]AggFunc
+}{m: map[string]AggFunc{}}
+
+func init() {
+	for _, a := range []AggFunc{AggSum, AggAvg, AggMin, AggMax, AggCount, AggCountDistinct, AggStdDev} {
+		aggFuncs.m[a.Name()] = a
+	}
+}
+
+// RegisterAggFunc makes a custom aggregate known by its name, so that a table
+// using it, e.g. as footer aggregate, can be restored from json
+func RegisterAggFunc(a AggFunc) {
+	aggFuncs.Lock()
+	defer aggFuncs.Unlock()
+	aggFuncs.m[a.Name()] = a
+}
+
+// lookupAggFunc returns the aggregate with the supplied name
+func lookupAggFunc(name string) (AggFunc, bool) {
+	aggFuncs.RLock()
+	a, ok := aggFuncs.m[name]
+	aggFuncs.RUnlock()
+	if ok {
+		return a, true
+	}
+	var p float64
+	if strings.HasPrefix(name, "percentile") {
+		if _, err := fmt.Sscanf(name, "percentile(%g)", &p); err == nil {
+			return AggPercentile(p), true
+		}
+	}
+	return nil, false
+}
+
+// AggPercentile returns the aggregate computing the p-th percentile, 0 <= p <= 100,
+// of numbers as float. Values between two numbers are interpolated linearly.
+func AggPercentile(p float64) AggFunc {
+	if p < 0 {
+		p = 0
+	}
+	if p > 100 {
+		p = 100
+	}
+	return aggFunc{fmt.Sprintf("percentile(%g)", p), func(cells []Cell) Cell {
+		return aggPercentile(cells, p)
+	}}
+}
+
+// HasValue reports whether a cell holds a value. An aggregate of cells none of
+// which fit it, e.g. the average of no numbers, has no value.
+func (c Cell) HasValue() bool {
+	return c.Type != 0
+}
+
+// isNumber reports whether a cell holds a number
+func (c Cell) isNumber() bool {
+	return c.Type == CELLINT || c.Type == CELLFLOAT
+}
+
+// isDate reports whether a cell holds a date or datetime
+func (c Cell) isDate() bool {
+	return c.Type == CELLDATE || c.Type == CELLDATETIME
+}
+
+// float returns the value of a number cell as float
+func (c Cell) float() float64 {
+	if c.Type == CELLINT {
+		return float64(c.Ival)
+	}
+	return c.Fval
+}
+
+func aggSum(cells []Cell) Cell {
+	s := Cell{Type: CELLINT}
+	for _, c := range cells {
+		switch {
+		case c.Type == CELLFLOAT || (c.Type == CELLINT && s.Type == CELLFLOAT):
+			s = Cell{Type: CELLFLOAT, Fval: s.float() + c.float()}
+		case c.Type == CELLINT:
+			s = Cell{Type: CELLINT, Ival: s.Ival + c.Ival}
+		}
+	}
+	return s
+}
+
+func aggAvg(cells []Cell) Cell {
+	var sum float64
+	var n int
+	for _, c := range cells {
+		if c.isNumber() {
+			sum += c.float()
+			n++
+		}
+	}
+	if n == 0 {
+		return Cell{}
+	}
+	return Cell{Type: CELLFLOAT, Fval: sum / float64(n)}
+}
+
+// aggExtreme returns the smallest cell if less is true, the largest otherwise.
+// Numbers are compared with numbers, dates with dates, whichever comes first
+// decides.
+func aggExtreme(cells []Cell, less bool) Cell {
+	var e Cell
+	for _, c := range cells {
+		var before bool
+		switch {
+		case c.isNumber() && (e.Type == 0 || e.isNumber()):
+			before = e.Type == 0 || c.float() < e.float()
+			if !less {
+				before = e.Type == 0 || c.float() > e.float()
+			}
+		case c.isDate() && (e.Type == 0 || e.isDate()):
+			before = e.Type == 0 || c.Dval.Before(e.Dval)
+			if !less {
+				before = e.Type == 0 || c.Dval.After(e.Dval)
+			}
+		}
+		if before {
+			e = c
+		}
+	}
+	return e
+}
+
+func aggMin(cells []Cell) Cell {
+	return aggExtreme(cells, true)
+}
+
+func aggMax(cells []Cell) Cell {
+	return aggExtreme(cells, false)
+}
+
+func aggCount(cells []Cell) Cell {
+	var n int64
+	for _, c := range cells {
+		if c.Type != 0 {
+			n++
+		}
+	}
+	return Cell{Type: CELLINT, Ival: n}
+}
+
+func aggCountDistinct(cells []Cell) Cell {
+	var values []Cell
+	for _, c := range cells {
+		if c.Type != 0 {
+			values = append(values, c)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return compareCells(values[i], values[j]) < 0 })
+	var n int64
+	for i := range values {
+		if i == 0 || compareCells(values[i-1], values[i]) != 0 {
+			n++
+		}
+	}
+	return Cell{Type: CELLINT, Ival: n}
+}
+
+// numbers returns the values of the number cells as floats
+func numbers(cells []Cell) []float64 {
+	var f []float64
+	for _, c := range cells {
+		if c.isNumber() {
+			f = append(f, c.float())
+		}
+	}
+	return f
+}
+
+func aggPercentile(cells []Cell, p float64) Cell {
+	f := numbers(cells)
+	if len(f) == 0 {
+		return Cell{}
+	}
+	sort.Float64s(f)
+	r := p / 100 * float64(len(f)-1)
+	i := int(r)
+	v := f[i]
+	if i+1 < len(f) {
+		v += (r - float64(i)) * (f[i+1] - f[i])
+	}
+	return Cell{Type: CELLFLOAT, Fval: v}
+}
+
+func aggStdDev(cells []Cell) Cell {
+	f := numbers(cells)
+	if len(f) < 2 {
+		return Cell{}
+	}
+	var mean, ss float64
+	for _, v := range f {
+		mean += v
+	}
+	mean /= float64(len(f))
+	for _, v := range f {
+		ss += (v - mean) * (v - mean)
+	}
+	return Cell{Type: CELLFLOAT, Fval: math.Sqrt(ss / float64(len(f)-1))}
+}
+
+// Aggregate computes the aggregate agg, e.g. AggAvg, of all the rows at the
+// specified column index. It returns a Cell
+func (t *Table) Aggregate(col int, agg AggFunc) Cell {
+	return t.AggregateRows(col, 0, len(t.Row)-1, agg)
+}
+
+// AggregateRows computes the aggregate agg of rows from thru to at the
+// specified column index. It returns a Cell
+func (t *Table) AggregateRows(col, from, to int, agg AggFunc) Cell {
+	if col < 0 || col >= len(t.ColDefs) {
+		return Cell{}
+	}
+	return t.aggregate(col, t.getColumnCells(col, from, to), agg)
+}
+
+// AggregateRowset computes the aggregate agg of the rows in rowset rsid at the
+// specified column index. It returns a Cell
+func (t *Table) AggregateRowset(rsid, col int, agg AggFunc) Cell {
+	if col < 0 || col >= len(t.ColDefs) {
+		return Cell{}
+	}
+	var cells []Cell
+	for _, row := range t.GetRowset(rsid) {
+		if row >= 0 && row < len(t.Row) {
+			cells = append(cells, t.Row[row].Col[col])
+		}
+	}
+	return t.aggregate(col, cells, agg)
+}
+
+// aggregate computes the aggregate agg of cells of column col. A sum of no
+// numbers is a zero of the type of the column.
+func (t *Table) aggregate(col int, cells []Cell, agg AggFunc) Cell {
+	c := agg.Aggregate(cells)
+	if a, ok := agg.(aggFunc); ok && a.name == "sum" && len(numbers(cells)) == 0 && t.ColDefs[col].CellType == CELLFLOAT {
+		c = Cell{Type: CELLFLOAT}
+	}
+	return c
+}
+
+// getColumnCells returns the cells of column col in rows from thru to
+func (t *Table) getColumnCells(col, from, to int) []Cell {
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(t.Row) {
+		to = len(t.Row) - 1
+	}
+	var cells []Cell
+	for i := from; i <= to; i++ {
+		cells = append(cells, t.Row[i].Col[col])
+	}
+	return cells
+}
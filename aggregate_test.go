@@ -0,0 +1,136 @@
+package gotable
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+// weightedAvg is a custom aggregate, the average of column 0 weighted by column 1
+type weightedAvg struct {
+	t *Table
+}
+
+func (w weightedAvg) Name() string { return "weighted" }
+func (w weightedAvg) Aggregate(cells []Cell) Cell {
+	var s, n float64
+	for i := range cells {
+		s += w.t.Row[i].Col[0].float() * w.t.Row[i].Col[1].float()
+		n += w.t.Row[i].Col[1].float()
+	}
+	return Cell{Type: CELLFLOAT, Fval: s / n}
+}
+
+func TestAggregate(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("int", 10, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("float", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("date", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	ints := []int64{4, 1, 3, 2, 10}
+	for i, v := range ints {
+		tbl.AddRow()
+		tbl.Puti(-1, 0, v)
+		tbl.Putf(-1, 1, float64(v)/2)
+		tbl.Putd(-1, 2, time.Date(2017, time.March, int(v), 0, 0, 0, 0, time.UTC))
+		tbl.Puts(-1, 3, []string{"a", "b", "a", "c", "b"}[i])
+	}
+	tbl.AddRow() // empty row is ignored by all but count of rows
+
+	for _, c := range []struct {
+		agg  AggFunc
+		col  int
+		want Cell
+	}{
+		{AggSum, 0, Cell{Type: CELLINT, Ival: 20}},
+		{AggAvg, 0, Cell{Type: CELLFLOAT, Fval: 4}},
+		{AggMin, 1, Cell{Type: CELLFLOAT, Fval: 0.5}},
+		{AggMax, 0, Cell{Type: CELLINT, Ival: 10}},
+		{AggCount, 0, Cell{Type: CELLINT, Ival: 5}},
+		{AggCountDistinct, 3, Cell{Type: CELLINT, Ival: 3}},
+		{AggMedian, 0, Cell{Type: CELLFLOAT, Fval: 3}},
+		{AggPercentile(25), 0, Cell{Type: CELLFLOAT, Fval: 2}},
+		{AggPercentile(90), 0, Cell{Type: CELLFLOAT, Fval: 7.6}},
+		{AggStdDev, 0, Cell{Type: CELLFLOAT, Fval: math.Sqrt(12.5)}},
+		{AggAvg, 3, Cell{}},    // no numbers, no value
+		{AggStdDev, 3, Cell{}}, // no numbers, no value
+	} {
+		got := tbl.Aggregate(c.col, c.agg)
+		if got.Type != c.want.Type || got.Ival != c.want.Ival || math.Abs(got.Fval-c.want.Fval) > 1e-9 {
+			t.Errorf("aggregate_test: Expected %s of column %d to be %#v, found %#v\n", c.agg.Name(), c.col, c.want, got)
+		}
+	}
+
+	// empty range, rows 5-5 hold no values
+	for _, c := range []struct {
+		agg  AggFunc
+		col  int
+		want Cell
+	}{
+		{AggSum, 0, Cell{Type: CELLINT}},
+		{AggSum, 1, Cell{Type: CELLFLOAT}}, // typed from the column
+		{AggSum, 3, Cell{Type: CELLINT}},
+		{AggCount, 1, Cell{Type: CELLINT}},
+		{AggCountDistinct, 1, Cell{Type: CELLINT}},
+		{AggAvg, 0, Cell{}},
+		{AggMin, 2, Cell{}},
+		{AggMax, 2, Cell{}},
+		{AggMedian, 1, Cell{}},
+		{AggPercentile(90), 1, Cell{}},
+		{AggStdDev, 1, Cell{}},
+	} {
+		got := tbl.AggregateRows(c.col, 5, 5, c.agg)
+		if got != c.want || got.HasValue() != (c.want.Type != 0) {
+			t.Errorf("aggregate_test: Expected %s of empty range of column %d to be %#v, found %#v\n", c.agg.Name(), c.col, c.want, got)
+		}
+		if got := tbl.AggregateRowset(tbl.CreateRowset(), c.col, c.agg); got != c.want {
+			t.Errorf("aggregate_test: Expected %s of empty rowset of column %d to be %#v, found %#v\n", c.agg.Name(), c.col, c.want, got)
+		}
+	}
+
+	// dates for min and max
+	if c := tbl.Aggregate(2, AggMax); c.Type != CELLDATE || c.Dval.Day() != 10 {
+		t.Errorf("aggregate_test: Expected latest date, found %#v\n", c)
+	}
+
+	// row ranges and rowsets
+	if c := tbl.AggregateRows(0, 1, 3, AggMax); c.Ival != 3 {
+		t.Errorf("aggregate_test: Expected max 3 of rows 1-3, found %#v\n", c)
+	}
+	rs := tbl.CreateRowset()
+	tbl.AppendToRowset(rs, 0)
+	tbl.AppendToRowset(rs, 4)
+	if c := tbl.AggregateRowset(rs, 1, AggAvg); c.Fval != 3.5 {
+		t.Errorf("aggregate_test: Expected average 3.5 of rowset, found %#v\n", c)
+	}
+	if c := tbl.AggregateRowset(rs+1, 0, AggCount); c.Type != CELLINT || c.Ival != 0 {
+		t.Errorf("aggregate_test: Expected count 0 of invalid rowset, found %#v\n", c)
+	}
+	if c := tbl.Aggregate(4, AggCount); c.Type != 0 {
+		t.Errorf("aggregate_test: Expected no value for invalid column, found %#v\n", c)
+	}
+
+	// custom aggregate
+	w := weightedAvg{&tbl}
+	if c := tbl.AggregateRows(0, 0, 4, w); math.Abs(c.Fval-6.5) > 1e-9 {
+		t.Errorf("aggregate_test: Expected weighted average 6.5, found %#v\n", c)
+	}
+
+	// percentile and registered aggregates survive json round-trip
+	RegisterAggFunc(w)
+	tbl.SetFooterAggregate(0, AggPercentile(90))
+	tbl.SetFooterAggregate(1, w)
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("aggregate_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("aggregate_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if len(tbl2.footerAggs) != 2 || tbl2.footerAggs[0].Name() != "percentile(90)" || tbl2.footerAggs[1].Name() != "weighted" {
+		t.Errorf("aggregate_test: Expected footer aggregates to be preserved, found %v\n", tbl2.footerAggs)
+	}
+}
@@ -0,0 +1,28 @@
+package gotable
+
+import (
+	"embed"
+	"strings"
+)
+
+// assets holds the default html template and css, the css is compiled from
+// scss/gotable.scss with `make css`
+//
+//go:embed assets
+var assets embed.FS
+
+// DCSS et. al. are the default css and html template, embedded in the package
+var (
+	DCSS      = mustReadAsset("assets/gotable.css")
+	DTEMPLATE = mustReadAsset("assets/gotable.tmpl")
+)
+
+// mustReadAsset returns the content of an embedded asset, it panics if the
+// asset is missing which can only happen with a broken build
+func mustReadAsset(name string) string {
+	b, err := assets.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return strings.TrimSpace(string(b))
+}
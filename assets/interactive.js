@@ -0,0 +1,144 @@
+function (opts) {
+	/* gotable interactive html: sorting, filtering and paging of the rows of
+	   the table container which holds this script. Each tbody is a group of
+	   rows between separator lines, rows never leave their group and the
+	   separator lines stay at the edges of the group. */
+	var c = document.currentScript.parentNode;
+	var table = c.querySelector("table");
+	if (!table || !table.tHead) {
+		return;
+	}
+	var head = table.tHead.rows[table.tHead.rows.length - 1];
+	var bodies = Array.prototype.slice.call(table.tBodies);
+	var query = "", page = 0, pages = 1, sortCol = -1, sortDir = 1;
+
+	bodies.forEach(function (b) {
+		var rows = b.rows;
+		b.topLine = rows.length > 0 && rows[0].classList.contains("top-line");
+		b.bottomLine = rows.length > 0 && rows[rows.length - 1].classList.contains("bottom-line");
+	});
+
+	function render() {
+		var q = query.toLowerCase(), n = 0, first = page * opts.pageSize;
+		bodies.forEach(function (b) {
+			/* lines go to the edges of the filtered group, not of the page */
+			var matched = [];
+			Array.prototype.forEach.call(b.rows, function (r) {
+				var show = !q || r.textContent.toLowerCase().indexOf(q) >= 0;
+				if (show) {
+					matched.push(r);
+					show = !opts.pageSize || (n >= first && n < first + opts.pageSize);
+					n++;
+				}
+				r.style.display = show ? "" : "none";
+				r.classList.remove("top-line", "bottom-line");
+			});
+			if (matched.length > 0) {
+				if (b.topLine) {
+					matched[0].classList.add("top-line");
+				}
+				if (b.bottomLine) {
+					matched[matched.length - 1].classList.add("bottom-line");
+				}
+			}
+		});
+		if (opts.pageSize) {
+			pages = Math.max(1, Math.ceil(n / opts.pageSize));
+			label.textContent = "Page " + (page + 1) + " of " + pages;
+			prev.disabled = page === 0;
+			next.disabled = page >= pages - 1;
+		}
+	}
+
+	function value(r, col, numeric) {
+		/* merged cells span columns, cells are found by their column */
+		var cell = r.querySelector('td[data-col="' + col + '"]');
+		if (!cell) {
+			return null;
+		}
+		if (numeric) {
+			var v = cell.getAttribute("data-v");
+			return v === null ? null : parseFloat(v);
+		}
+		var s = cell.textContent.trim();
+		return s === "" ? null : s;
+	}
+
+	function sortBy(th, col) {
+		sortDir = sortCol === col ? -sortDir : 1;
+		sortCol = col;
+		var numeric = th.getAttribute("data-type") === "num";
+		bodies.forEach(function (b) {
+			/* merged rows can not be reordered, such a group keeps its order */
+			if (b.querySelector("td[rowspan]")) {
+				return;
+			}
+			var rows = Array.prototype.slice.call(b.rows);
+			rows.sort(function (x, y) {
+				var a = value(x, col, numeric), z = value(y, col, numeric);
+				if (a === null || z === null) {
+					/* blank cells always go last */
+					return (a === null) - (z === null);
+				}
+				if (numeric) {
+					return sortDir * (a - z);
+				}
+				return sortDir * a.localeCompare(z);
+			});
+			rows.forEach(function (r) {
+				b.appendChild(r);
+			});
+		});
+		Array.prototype.forEach.call(head.cells, function (h) {
+			h.classList.remove("sort-asc", "sort-desc");
+		});
+		th.classList.add(sortDir > 0 ? "sort-asc" : "sort-desc");
+		render();
+	}
+
+	var controls = document.createElement("div");
+	controls.className = "interactive-controls";
+	c.insertBefore(controls, table);
+
+	if (opts.filter) {
+		var input = document.createElement("input");
+		input.type = "search";
+		input.placeholder = "Filter";
+		input.addEventListener("input", function () {
+			query = input.value;
+			page = 0;
+			render();
+		});
+		controls.appendChild(input);
+	}
+
+	if (opts.pageSize) {
+		var prev = document.createElement("button"), next = document.createElement("button");
+		var label = document.createElement("span");
+		prev.type = next.type = "button";
+		prev.textContent = "Previous";
+		next.textContent = "Next";
+		prev.addEventListener("click", function () {
+			page = Math.max(0, page - 1);
+			render();
+		});
+		next.addEventListener("click", function () {
+			page = Math.min(pages - 1, page + 1);
+			render();
+		});
+		controls.appendChild(prev);
+		controls.appendChild(label);
+		controls.appendChild(next);
+	}
+
+	if (opts.sort) {
+		Array.prototype.forEach.call(head.cells, function (th, col) {
+			th.classList.add("sortable");
+			th.addEventListener("click", function () {
+				sortBy(th, col);
+			});
+		});
+	}
+
+	render();
+}
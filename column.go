@@ -0,0 +1,299 @@
+package gotable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HIDDENALL in ColumnDef.Hidden hides a column in all export formats, an
+// entry of HIDDENEXCEPT followed by a format, e.g. "!html", shows it in that
+// format nonetheless
+const (
+	HIDDENALL    = "*"
+	HIDDENEXCEPT = "!"
+)
+
+// InsertColumn inserts a new column before the column at index col, with col
+// equal to the number of columns it is appended. The cells of the new column
+// are empty. Cells, css, merges, header groups and footer aggregates of the
+// columns after it move along.
+func (t *Table) InsertColumn(col int, title string, width, celltype int, justification int) error {
+	if col != len(t.ColDefs) {
+		if err := t.HasValidColumn(col); err != nil {
+			return err
+		}
+	}
+	var cd = ColumnDef{
+		ColTitle: title, Width: width,
+		CellType: celltype, Justify: justification,
+		Fdecimals: 2, HTMLWidth: -1,
+	}
+	t.AdjustColumnHeader(&cd)
+	t.AdjustFormatString(&cd)
+
+	cols := append([]ColumnDef(nil), t.ColDefs[:col]...)
+	cols = append(cols, cd)
+	cols = append(cols, t.ColDefs[col:]...)
+	from := t.columnIndexes()
+	from = append(from[:col], append([]int{-1}, from[col:]...)...)
+	t.reorderColumns(cols, from)
+	return nil
+}
+
+// DeleteColumn removes the column at index col along with its cells, css and
+// footer aggregate. Merges and header groups shrink, they are removed if they
+// covered only this column.
+func (t *Table) DeleteColumn(col int) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	from := t.columnIndexes()
+	from = append(from[:col], from[col+1:]...)
+	t.reorderColumns(nil, from)
+	return nil
+}
+
+// MoveColumn moves the column at index from to index to, the columns in between
+// shift by one. Merges and header groups which are split apart by the move are
+// removed.
+func (t *Table) MoveColumn(from, to int) error {
+	for _, c := range []int{from, to} {
+		if err := t.HasValidColumn(c); err != nil {
+			return err
+		}
+	}
+	idx := t.columnIndexes()
+	idx = append(idx[:from], idx[from+1:]...)
+	idx = append(idx[:to], append([]int{from}, idx[to:]...)...)
+	t.reorderColumns(nil, idx)
+	return nil
+}
+
+// SetColumnHidden hides or shows the column at index col in the supplied export
+// formats, e.g. FORMATHTML, or in all formats if none is supplied. A hidden
+// column is left out of the output as if it was deleted, it is still part of
+// the table. Hiding in html output hides in html fragments too. Showing a column
+// hidden in all formats in some of them keeps it hidden in all the others,
+// formats registered later included.
+func (t *Table) SetColumnHidden(col int, hidden bool, formats ...string) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	cd := &t.ColDefs[col]
+	if len(formats) == 0 {
+		cd.Hidden = nil
+		if hidden {
+			cd.Hidden = []string{HIDDENALL}
+		}
+		return nil
+	}
+	for _, format := range formats {
+		format = strings.ToLower(format)
+		var h []string
+		all := false
+		for _, f := range cd.Hidden {
+			name := strings.TrimPrefix(f, HIDDENEXCEPT)
+			if f == HIDDENALL {
+				all = true
+			}
+			if name != format && !(format == FORMATHTML && name == FORMATFRAGMENT) {
+				h = append(h, f)
+			}
+		}
+		switch {
+		case hidden && !all:
+			h = append(h, format)
+		case !hidden && all:
+			h = append(h, HIDDENEXCEPT+format)
+		}
+		cd.Hidden = h
+	}
+	return nil
+}
+
+// IsColumnHidden reports whether the column at index col is hidden in the
+// export format
+func (t *Table) IsColumnHidden(col int, format string) bool {
+	if col < 0 || col >= len(t.ColDefs) {
+		return false
+	}
+	format = strings.ToLower(format)
+	hidden := false
+	for _, f := range t.ColDefs[col].Hidden {
+		name := strings.TrimPrefix(f, HIDDENEXCEPT)
+		// html fragments are html output too
+		if name != format && !(name == FORMATHTML && format == FORMATFRAGMENT) && f != HIDDENALL {
+			continue
+		}
+		if f != name { // excepted
+			return false
+		}
+		hidden = true
+	}
+	return hidden
+}
+
+// visible returns the table as it is rendered in the export format, i.e. t
+// itself or a copy without the hidden columns
+func (t *Table) visible(format string) *Table {
+	var from []int
+	var cols []ColumnDef
+	for i := range t.ColDefs {
+		if !t.IsColumnHidden(i, format) {
+			from = append(from, i)
+			cols = append(cols, t.ColDefs[i])
+		}
+	}
+	if len(from) == len(t.ColDefs) {
+		return t
+	}
+	d := *t
+	d.reorderColumns(cols, from)
+	return &d
+}
+
+// columnIndexes returns the indexes of all columns
+func (t *Table) columnIndexes() []int {
+	idx := make([]int, len(t.ColDefs))
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+// reorderColumns replaces the columns of the table, from[i] is the current index
+// of column i or -1 for a new column whose definition is cols[i]. If cols is nil
+// the definitions are taken from the current columns. Cells, css, merges, header
+// groups and footer aggregates follow their columns, anything which belongs to
+// a column not in from is removed. A merge or header group is kept if its
+// columns stay in order next to each other, new columns in between widen it.
+// Everything is replaced rather than changed, a copy of the table may share it.
+func (t *Table) reorderColumns(cols []ColumnDef, from []int) {
+	if cols == nil {
+		cols = make([]ColumnDef, len(from))
+		for i, c := range from {
+			cols[i] = t.ColDefs[c]
+		}
+	}
+	to := make(map[int]int, len(from))
+	for i, c := range from {
+		if c >= 0 {
+			to[c] = i
+		}
+	}
+	remapCols := func(cs []Colset) []Colset {
+		if cs == nil {
+			return nil
+		}
+		r := make([]Colset, len(cs))
+		for i := range cs {
+			r[i] = Colset{Col: make([]Cell, len(from)), Height: cs[i].Height, cssSeq: cs[i].cssSeq}
+			for j, c := range from {
+				if c >= 0 {
+					r[i].Col[j] = cs[i].Col[c]
+				}
+			}
+		}
+		return r
+	}
+	// span returns the new first and last column of columns first thru last,
+	// ok is false if they don't stay together
+	span := func(first, last int) (lo, hi int, ok bool) {
+		lo, hi = -1, -1
+		for c := first; c <= last; c++ {
+			if i, found := to[c]; found {
+				if lo < 0 {
+					lo = i
+				} else if i <= hi {
+					return 0, 0, false
+				}
+				hi = i
+			}
+		}
+		if lo < 0 {
+			return 0, 0, false
+		}
+		for i := lo; i <= hi; i++ {
+			if from[i] >= 0 && (from[i] < first || from[i] > last) {
+				return 0, 0, false
+			}
+		}
+		return lo, hi, true
+	}
+
+	rows := remapCols(t.Row)
+	var merges []CellMerge
+	for _, m := range t.Merges {
+		lo, hi, ok := span(m.Col, m.Col+m.ColSpan-1)
+		if !ok || (hi == lo && m.RowSpan == 1) {
+			continue
+		}
+		if _, found := to[m.Col]; !found {
+			rows[m.Row].Col[lo] = t.Row[m.Row].Col[m.Col] // value of the merge moves to its new first column
+		}
+		m.Col, m.ColSpan = lo, hi-lo+1
+		merges = append(merges, m)
+	}
+
+	var groups []HeaderGroup
+	for _, g := range t.HeaderGroups {
+		lo, hi, ok := span(g.FromCol, g.ToCol)
+		if !ok {
+			continue
+		}
+		g.FromCol, g.ToCol = lo, hi
+		dup := false
+		for i := range groups {
+			dup = dup || (groups[i].FromCol == lo && groups[i].ToCol == hi)
+		}
+		if !dup {
+			groups = append(groups, g)
+		}
+	}
+
+	var aggs map[int]AggFunc
+	for c, agg := range t.footerAggs {
+		if i, ok := to[c]; ok {
+			if aggs == nil {
+				aggs = make(map[int]AggFunc)
+			}
+			aggs[i] = agg
+		}
+	}
+
+	// css keys of headers, columns and cells hold the column index
+	var css map[string]map[string]*CSSProperty
+	if t.CSS != nil {
+		css = make(map[string]map[string]*CSSProperty, len(t.CSS))
+	}
+	for k, v := range t.CSS {
+		var r, c int
+		if n, _ := fmt.Sscanf(k, "header-%d", &c); n == 1 && k == t.getCSSMapKeyForHeaderCell(c) {
+			if i, ok := to[c]; ok {
+				css[t.getCSSMapKeyForHeaderCell(i)] = v
+			}
+			continue
+		}
+		if n, _ := fmt.Sscanf(k, "col-%d", &c); n == 1 && k == t.getCSSMapKeyForCol(c) {
+			if i, ok := to[c]; ok {
+				css[t.getCSSMapKeyForCol(i)] = v
+			}
+			continue
+		}
+		if n, _ := fmt.Sscanf(k, "row:%d-col:%d", &r, &c); n == 2 && k == t.getCSSMapKeyForCell(r, c) {
+			if i, ok := to[c]; ok {
+				css[t.getCSSMapKeyForCell(r, i)] = v
+			}
+			continue
+		}
+		css[k] = v
+	}
+
+	t.ColDefs = cols
+	t.Row = rows
+	t.Footer = remapCols(t.Footer)
+	t.Merges = merges
+	t.HeaderGroups = groups
+	t.footerAggs = aggs
+	t.CSS = css
+}
@@ -0,0 +1,182 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnManagement(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("ID", 4, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Name", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Q1", 6, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Q2", 6, CELLINT, COLJUSTIFYRIGHT)
+	for i, name := range []string{"north", "south"} {
+		tbl.AddRow()
+		tbl.Puti(-1, 0, int64(i+1))
+		tbl.Puts(-1, 1, name)
+		tbl.Puti(-1, 2, int64(10*(i+1)))
+		tbl.Puti(-1, 3, int64(20*(i+1)))
+	}
+	tbl.SetHeaderCellCSS(2, []*CSSProperty{{Name: "color", Value: "red"}})
+	tbl.SetColCSS(3, []*CSSProperty{{Name: "color", Value: "blue"}})
+	tbl.SetCellCSS(0, 3, []*CSSProperty{{Name: "color", Value: "green"}})
+	tbl.MergeCells(0, 2, 1, 2)
+	tbl.AddHeaderGroup("Sales", 2, 3)
+	tbl.SetFooterAggregate(3, AggSum)
+
+	titles := func() string {
+		var s []string
+		for _, cd := range tbl.ColDefs {
+			s = append(s, cd.ColTitle)
+		}
+		return strings.Join(s, ",")
+	}
+	cssKeys := func() []string {
+		var s []string
+		for _, k := range []string{"header-2", "col-3", "col-4", "row:0-col:3", "row:0-col:4"} {
+			if _, ok := tbl.CSS[k]; ok {
+				s = append(s, k)
+			}
+		}
+		return s
+	}
+
+	// new column inside the merge and the header group widens them
+	if err := tbl.InsertColumn(3, "Q1b", 6, CELLINT, COLJUSTIFYRIGHT); err != nil {
+		t.Fatalf("column_test: Error inserting column: %s\n", err.Error())
+	}
+	if titles() != "ID,Name,Q1,Q1b,Q2" || tbl.Geti(1, 4) != 40 || tbl.Get(1, 3).Type != 0 {
+		t.Errorf("column_test: Unexpected columns after insert: %s, %v\n", titles(), tbl.Row[1].Col)
+	}
+	if !reflect.DeepEqual(tbl.Merges, []CellMerge{{0, 2, 1, 3}}) || !reflect.DeepEqual(tbl.HeaderGroups, []HeaderGroup{{"Sales", 2, 4}}) {
+		t.Errorf("column_test: Expected merge and header group to widen, found %v %v\n", tbl.Merges, tbl.HeaderGroups)
+	}
+	if k := cssKeys(); !reflect.DeepEqual(k, []string{"header-2", "col-4", "row:0-col:4"}) {
+		t.Errorf("column_test: Expected css to follow its column, found %v\n", k)
+	}
+	if tbl.GetFooter(0, 4).Ival != 60 || tbl.GetFooter(0, 3).Type != 0 {
+		t.Errorf("column_test: Expected footer aggregate to follow its column, found %v\n", tbl.getFooterRow(0))
+	}
+
+	// moving a column keeps others in order
+	if err := tbl.MoveColumn(1, 0); err != nil {
+		t.Fatalf("column_test: Error moving column: %s\n", err.Error())
+	}
+	if titles() != "Name,ID,Q1,Q1b,Q2" || tbl.Gets(0, 0) != "north" || tbl.Geti(0, 1) != 1 {
+		t.Errorf("column_test: Unexpected columns after move: %s\n", titles())
+	}
+
+	// deleting the first column of the merge moves the value of the merge
+	if err := tbl.DeleteColumn(2); err != nil {
+		t.Fatalf("column_test: Error deleting column: %s\n", err.Error())
+	}
+	if titles() != "Name,ID,Q1b,Q2" || tbl.Geti(0, 2) != 10 || tbl.Geti(1, 2) != 0 {
+		t.Errorf("column_test: Unexpected columns after delete: %s, %v\n", titles(), tbl.Row)
+	}
+	if !reflect.DeepEqual(tbl.Merges, []CellMerge{{0, 2, 1, 2}}) || !reflect.DeepEqual(tbl.HeaderGroups, []HeaderGroup{{"Sales", 2, 3}}) {
+		t.Errorf("column_test: Expected merge and header group to shrink, found %v %v\n", tbl.Merges, tbl.HeaderGroups)
+	}
+	if _, ok := tbl.CSS["header-2"]; ok {
+		t.Errorf("column_test: Expected header css of deleted column to be removed\n")
+	}
+
+	// moving a column out of the merge splits it apart
+	if err := tbl.MoveColumn(3, 0); err != nil {
+		t.Fatalf("column_test: Error moving column: %s\n", err.Error())
+	}
+	if len(tbl.Merges) != 0 || len(tbl.HeaderGroups) != 0 {
+		t.Errorf("column_test: Expected merge and header group to be removed, found %v %v\n", tbl.Merges, tbl.HeaderGroups)
+	}
+	for _, c := range [][2]int{{-1, 0}, {4, 0}, {0, 4}} {
+		if err := tbl.MoveColumn(c[0], c[1]); err == nil {
+			t.Errorf("column_test: Expected error moving column %d to %d\n", c[0], c[1])
+		}
+	}
+	if err := tbl.InsertColumn(5, "X", 1, CELLINT, COLJUSTIFYRIGHT); err == nil {
+		t.Errorf("column_test: Expected error inserting column beyond the end\n")
+	}
+	if err := tbl.InsertColumn(4, "Note", 10, CELLSTRING, COLJUSTIFYLEFT); err != nil || titles() != "Q2,Name,ID,Q1b,Note" {
+		t.Errorf("column_test: Expected column to be appended, found %s\n", titles())
+	}
+}
+
+func TestColumnHidden(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("InternalID", 10, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Name", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puti(-1, 0, 4711)
+	tbl.Puts(-1, 1, "north")
+	tbl.SetColCSS(1, []*CSSProperty{{Name: "color", Value: "red"}})
+
+	tbl.SetColumnHidden(0, true, FORMATHTML, FORMATTEXT)
+	for _, c := range []struct {
+		format string
+		hidden bool
+	}{{FORMATHTML, true}, {FORMATFRAGMENT, true}, {FORMATTEXT, true}, {FORMATCSV, false}, {FORMATJSON, false}} {
+		var temp bytes.Buffer
+		if err := tbl.Export(&temp, c.format); err != nil {
+			t.Errorf("column_test: Error creating %s output: %s\n", c.format, err.Error())
+		}
+		if strings.Contains(temp.String(), "4711") == c.hidden || !strings.Contains(temp.String(), "north") {
+			t.Errorf("column_test: Expected column hidden %t in %s output, found:\n%s\n", c.hidden, c.format, temp.String())
+		}
+	}
+
+	// css of columns after a hidden column follows them
+	var temp bytes.Buffer
+	tbl.HTMLprintTable(&temp)
+	if !strings.Contains(temp.String(), "table tbody tr td.col-0{color:red;") {
+		t.Errorf("column_test: Expected css of visible column, found:\n%s\n", temp.String())
+	}
+	if tbl.ColDefs[0].ColTitle != "InternalID" || len(tbl.Row[0].Col) != 2 {
+		t.Errorf("column_test: Expected hidden column to stay in the table\n")
+	}
+
+	// hidden in all formats but csv
+	tbl.SetColumnHidden(0, true)
+	tbl.SetColumnHidden(0, false, FORMATCSV)
+	if !tbl.IsColumnHidden(0, FORMATMARKDOWN) || tbl.IsColumnHidden(0, FORMATCSV) || tbl.IsColumnHidden(1, FORMATCSV) {
+		t.Errorf("column_test: Expected column hidden in all formats but csv, found %v\n", tbl.ColDefs[0].Hidden)
+	}
+	if !reflect.DeepEqual(tbl.ColDefs[0].Hidden, []string{HIDDENALL, "!csv"}) {
+		t.Errorf("column_test: Expected csv excepted from hidden in all formats, found %v\n", tbl.ColDefs[0].Hidden)
+	}
+	if !tbl.IsColumnHidden(0, "sheet") { // formats registered later are hidden too
+		t.Errorf("column_test: Expected column hidden in any other format\n")
+	}
+
+	// html and html fragments go together
+	tbl.SetColumnHidden(0, false, FORMATHTML)
+	if tbl.IsColumnHidden(0, FORMATHTML) || tbl.IsColumnHidden(0, FORMATFRAGMENT) || !tbl.IsColumnHidden(0, FORMATTEXT) {
+		t.Errorf("column_test: Expected column shown in html and html fragments, found %v\n", tbl.ColDefs[0].Hidden)
+	}
+	tbl.SetColumnHidden(0, true, FORMATHTML, FORMATCSV)
+	if !reflect.DeepEqual(tbl.ColDefs[0].Hidden, []string{HIDDENALL}) {
+		t.Errorf("column_test: Expected exceptions to be removed, found %v\n", tbl.ColDefs[0].Hidden)
+	}
+	tbl.SetColumnHidden(0, false, FORMATCSV)
+
+	// hidden columns survive json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("column_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("column_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if !tbl2.IsColumnHidden(0, FORMATPDF) || tbl2.IsColumnHidden(0, FORMATCSV) {
+		t.Errorf("column_test: Expected hidden column to be preserved, found %v\n", tbl2.ColDefs[0].Hidden)
+	}
+	tbl.SetColumnHidden(0, false)
+	if tbl.ColDefs[0].Hidden != nil {
+		t.Errorf("column_test: Expected column to be shown in all formats, found %v\n", tbl.ColDefs[0].Hidden)
+	}
+}
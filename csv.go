@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"strings"
-
-	"github.com/dustin/go-humanize"
 )
 
+// CSVFOOTER is the only field of the record which precedes the footer rows in
+// csv output, it tells them apart from the data rows
+const CSVFOOTER = "#footer"
+
 // CSVTable struct used to prepare table in html version
 type CSVTable struct {
 	*Table
@@ -40,7 +42,12 @@ func (ct *CSVTable) writeTableOutput(w io.Writer) error {
 
 		// append rows
 		if rowsStr, err := ct.getRows(); err != nil {
-			tableOut += stringln(fmt.Sprintf("%q", err.Error()))
+			// footer, e.g. a count of 0, is shown under the headers without rows
+			if len(ct.Table.Footer) > 0 {
+				tableOut += headerStr + stringln(fmt.Sprintf("%q", err.Error())) + ct.getFooter()
+			} else {
+				tableOut += stringln(fmt.Sprintf("%q", err.Error()))
+			}
 		} else {
 			tableOut += headerStr
 			tableOut += rowsStr
@@ -101,6 +108,23 @@ func (ct *CSVTable) getHeaders() (string, error) {
 		return "", blankHdrsErr
 	}
 
+	// header groups are stacked above the column headers, like merged cells
+	// the title is in the first column of a group unless it is repeated
+	var s string
+	for _, groups := range ct.Table.getHeaderGroupRows() {
+		var tGroup []string
+		for _, g := range groups {
+			for i := g.FromCol; i <= g.ToCol; i++ {
+				if i == g.FromCol || ct.Table.csvMergeRepeat {
+					tGroup = append(tGroup, fmt.Sprintf("%q", g.Title))
+				} else {
+					tGroup = append(tGroup, `""`)
+				}
+			}
+		}
+		s += stringln(strings.Join(tGroup, ct.CellSep))
+	}
+
 	// format headers
 	var tHeader []string
 
@@ -109,7 +133,7 @@ func (ct *CSVTable) getHeaders() (string, error) {
 	}
 
 	// append last newLine char
-	return stringln(strings.Join(tHeader, ct.CellSep)), nil
+	return s + stringln(strings.Join(tHeader, ct.CellSep)), nil
 }
 
 func (ct *CSVTable) getRows() (string, error) {
@@ -126,7 +150,25 @@ func (ct *CSVTable) getRows() (string, error) {
 		rowsStr += s
 	}
 
-	return rowsStr, nil
+	return rowsStr + ct.getFooter(), nil
+}
+
+// getFooter returns the footer rows, the final rows, after a record holding
+// CSVFOOTER only
+func (ct *CSVTable) getFooter() string {
+	if len(ct.Table.Footer) == 0 {
+		return ""
+	}
+	s := stringln(fmt.Sprintf("%q", CSVFOOTER))
+	for i := 0; i < len(ct.Table.Footer); i++ {
+		var tRow []string
+		cs := ct.Table.getFooterRow(i)
+		for j := 0; j < len(cs.Col); j++ {
+			tRow = append(tRow, ct.getCellValue(cs.Col[j], j))
+		}
+		s += stringln(strings.Join(tRow, ct.CellSep))
+	}
+	return s
 }
 
 func (ct *CSVTable) getRow(row int) (string, error) {
@@ -145,24 +187,44 @@ func (ct *CSVTable) getRow(row int) (string, error) {
 	var tRow []string
 
 	for i := 0; i < len(ct.Table.Row[row].Col); i++ {
-
-		switch ct.Table.Row[row].Col[i].Type {
-		case CELLFLOAT:
-			tRow = append(tRow, fmt.Sprintf(ct.Table.ColDefs[i].Pfmt, humanize.FormatFloat("#,###.##", ct.Table.Row[row].Col[i].Fval)))
-		case CELLINT:
-			tRow = append(tRow, fmt.Sprintf(ct.Table.ColDefs[i].Pfmt, ct.Table.Row[row].Col[i].Ival))
-		case CELLSTRING:
-			// FOR CSV, APPEND FULL STRING, THERE ARE NO MULTILINE STRING IN THIS
-			tRow = append(tRow, fmt.Sprintf("%q", ct.Table.Row[row].Col[i].Sval))
-		case CELLDATE:
-			tRow = append(tRow, fmt.Sprintf("%*.*s", ct.Table.ColDefs[i].Width, ct.Table.ColDefs[i].Width, ct.Table.Row[row].Col[i].Dval.Format(ct.Table.DateFmt)))
-		case CELLDATETIME:
-			tRow = append(tRow, fmt.Sprintf("%*.*s", ct.Table.ColDefs[i].Width, ct.Table.ColDefs[i].Width, ct.Table.Row[row].Col[i].Dval.Format(ct.Table.DateTimeFmt)))
-		default:
-			tRow = append(tRow, mkstr(ct.Table.ColDefs[i].Width, ' '))
+		r, c := row, i
+		if m := ct.Table.getMerge(row, i); m != nil {
+			// cells hidden by a merge are blank unless the merged value is repeated
+			if (m.Row != row || m.Col != i) && !ct.Table.csvMergeRepeat {
+				tRow = append(tRow, `""`)
+				continue
+			}
+			r, c = m.Row, m.Col
 		}
+		tRow = append(tRow, ct.getCellValue(ct.Table.Row[r].Col[c], c))
 	}
 
 	// append newline char at last
 	return stringln(strings.Join(tRow, ct.CellSep)), nil
 }
+
+// getCellValue returns the csv formatted value of the cell in column col
+func (ct *CSVTable) getCellValue(c Cell, col int) string {
+	cd := ct.Table.ColDefs[col]
+	switch c.Type {
+	case CELLFLOAT:
+		return cd.floatText(c.Fval)
+	case CELLINT:
+		return fmt.Sprintf(cd.Pfmt, c.Ival)
+	case CELLSTRING:
+		// FOR CSV, APPEND FULL STRING, THERE ARE NO MULTILINE STRING IN THIS
+		return fmt.Sprintf("%q", c.Sval)
+	case CELLDATE:
+		return fmt.Sprintf("%*.*s", cd.Width, cd.Width, c.Dval.Format(ct.Table.DateFmt))
+	case CELLDATETIME:
+		return fmt.Sprintf("%*.*s", cd.Width, cd.Width, c.Dval.Format(ct.Table.DateTimeFmt))
+	}
+	return mkstr(cd.Width, ' ')
+}
+
+// SetCSVMergeRepeat sets how cells hidden by a merge or a header group are
+// written in csv output, csv has no merged cells so they are either blank
+// (default) or repeat the value of the merged cell
+func (t *Table) SetCSVMergeRepeat(repeat bool) {
+	t.csvMergeRepeat = repeat
+}
@@ -0,0 +1,210 @@
+package gotable
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FORMATTEXT et. al. are the names of built-in export formats
+const (
+	FORMATTEXT     = "text"
+	FORMATCSV      = "csv"
+	FORMATHTML     = "html"
+	FORMATFRAGMENT = "htmlfragment"
+	FORMATPDF      = "pdf"
+	FORMATMARKDOWN = "markdown"
+	FORMATJSON     = "json"
+	FORMATXLSX     = "xlsx"
+)
+
+// Exporter renders a table in some output format. Implement it to plug in a
+// custom format and register it with RegisterFormat.
+type Exporter interface {
+	Export(w io.Writer, t *Table) error
+}
+
+// ExporterFunc is an adapter to use an ordinary function as Exporter
+type ExporterFunc func(w io.Writer, t *Table) error
+
+// Export calls f(w, t)
+func (f ExporterFunc) Export(w io.Writer, t *Table) error {
+	return f(w, t)
+}
+
+// ExporterFactory returns a new Exporter configured with the options passed to
+// Table.Export. It should return an error for the options it doesn't understand
+type ExporterFactory func(opts ...interface{}) (Exporter, error)
+
+// formats holds the registered export formats
+var formats = struct {
+	sync.RWMutex
+	m map[string]ExporterFactory
+}{m: map[string]ExporterFactory{}}
+
+// RegisterFormat makes an export format available by the provided name to
+// Table.Export. Names are case insensitive. It returns an error if factory is
+// nil or the name is already registered.
+func RegisterFormat(name string, factory ExporterFactory) error {
+	if factory == nil {
+		return fmt.Errorf("Export format %s has nil factory", name)
+	}
+	name = strings.ToLower(name)
+
+	formats.Lock()
+	defer formats.Unlock()
+	if _, ok := formats.m[name]; ok {
+		return fmt.Errorf("Export format %s is already registered", name)
+	}
+	formats.m[name] = factory
+	return nil
+}
+
+// Formats returns a sorted list of the names of the registered export formats
+func Formats() []string {
+	formats.RLock()
+	defer formats.RUnlock()
+
+	var names []string
+	for name := range formats.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Export renders the entire table in the named format. Options are passed to
+// the factory of the format, built-in formats accept:
+//
+//	csv:  string, the cell separator
+//	htmlfragment: string, the container id
+//	json: int, the row layout JSONROWOBJECT or JSONROWARRAY
+//	pdf:  PDFOptions and/or context.Context
+func (t *Table) Export(w io.Writer, format string, opts ...interface{}) error {
+	formats.RLock()
+	factory, ok := formats.m[strings.ToLower(format)]
+	formats.RUnlock()
+	if !ok {
+		return fmt.Errorf("Unknown export format: %s", format)
+	}
+
+	e, err := factory(opts...)
+	if err != nil {
+		return err
+	}
+	return e.Export(w, t.visible(format))
+}
+
+// unsupportedOption returns error for an option which a format doesn't understand
+func unsupportedOption(format string, opt interface{}) error {
+	return fmt.Errorf("Unsupported option %T for export format %s", opt, format)
+}
+
+// built-in formats
+func init() {
+	RegisterFormat(FORMATTEXT, func(opts ...interface{}) (Exporter, error) {
+		for _, opt := range opts {
+			return nil, unsupportedOption(FORMATTEXT, opt)
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.TextprintTable(w)
+		}), nil
+	})
+
+	RegisterFormat(FORMATCSV, func(opts ...interface{}) (Exporter, error) {
+		sep := ","
+		for _, opt := range opts {
+			switch v := opt.(type) {
+			case string:
+				sep = v
+			default:
+				return nil, unsupportedOption(FORMATCSV, opt)
+			}
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			var tout TableExportType = &CSVTable{Table: t, CellSep: sep}
+			return tout.writeTableOutput(w)
+		}), nil
+	})
+
+	RegisterFormat(FORMATHTML, func(opts ...interface{}) (Exporter, error) {
+		for _, opt := range opts {
+			return nil, unsupportedOption(FORMATHTML, opt)
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.HTMLprintTable(w)
+		}), nil
+	})
+
+	RegisterFormat(FORMATFRAGMENT, func(opts ...interface{}) (Exporter, error) {
+		var id string
+		for _, opt := range opts {
+			switch v := opt.(type) {
+			case string:
+				id = v
+			default:
+				return nil, unsupportedOption(FORMATFRAGMENT, opt)
+			}
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.HTMLFragmentprintTable(w, id)
+		}), nil
+	})
+
+	RegisterFormat(FORMATPDF, func(opts ...interface{}) (Exporter, error) {
+		pdfOpts := DefaultPDFOptions()
+		ctx := context.Background()
+		for _, opt := range opts {
+			switch v := opt.(type) {
+			case PDFOptions:
+				pdfOpts = v
+			case context.Context:
+				ctx = v
+			default:
+				return nil, unsupportedOption(FORMATPDF, opt)
+			}
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.PDFprintTableWithOptionsContext(ctx, w, pdfOpts)
+		}), nil
+	})
+
+	RegisterFormat(FORMATMARKDOWN, func(opts ...interface{}) (Exporter, error) {
+		for _, opt := range opts {
+			return nil, unsupportedOption(FORMATMARKDOWN, opt)
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.MarkdownprintTable(w)
+		}), nil
+	})
+
+	RegisterFormat(FORMATJSON, func(opts ...interface{}) (Exporter, error) {
+		layout := JSONROWOBJECT
+		for _, opt := range opts {
+			switch v := opt.(type) {
+			case int:
+				layout = v
+			default:
+				return nil, unsupportedOption(FORMATJSON, opt)
+			}
+		}
+		if err := checkJSONLayout(layout); err != nil {
+			return nil, err
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.JSONprintTableLayout(w, layout)
+		}), nil
+	})
+
+	RegisterFormat(FORMATXLSX, func(opts ...interface{}) (Exporter, error) {
+		for _, opt := range opts {
+			return nil, unsupportedOption(FORMATXLSX, opt)
+		}
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			return t.XLSXprintTable(w)
+		}), nil
+	})
+}
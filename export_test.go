@@ -0,0 +1,84 @@
+package gotable
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Export Table")
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 6, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "apple")
+	tbl.Puti(-1, 1, 42)
+
+	// built-in formats must give the same output as their print methods
+	var expected, got bytes.Buffer
+	if err := tbl.CSVprintTable(&expected); err != nil {
+		t.Errorf("export_test: Error creating CSV output: %s\n", err.Error())
+	}
+	if err := tbl.Export(&got, "CSV"); err != nil {
+		t.Errorf("export_test: Error exporting csv: %s\n", err.Error())
+	}
+	if expected.String() != got.String() {
+		t.Errorf("export_test: Expected %q, but found %q\n", expected.String(), got.String())
+	}
+
+	got.Reset()
+	if err := tbl.Export(&got, FORMATCSV, ";"); err != nil {
+		t.Errorf("export_test: Error exporting csv: %s\n", err.Error())
+	}
+	if !strings.Contains(got.String(), `"Name";"Count"`) {
+		t.Errorf("export_test: Expected custom separator, but found %q\n", got.String())
+	}
+
+	if err := tbl.Export(&got, FORMATMARKDOWN, 1); err == nil {
+		t.Errorf("export_test: Expected error for unsupported option\n")
+	}
+	if err := tbl.Export(&got, "nosuchformat"); err == nil {
+		t.Errorf("export_test: Expected error for unknown format\n")
+	}
+
+	// custom format
+	err := RegisterFormat("fixed", func(opts ...interface{}) (Exporter, error) {
+		return ExporterFunc(func(w io.Writer, t *Table) error {
+			for i := 0; i < t.RowCount(); i++ {
+				fmt.Fprintf(w, "%-8s%04d\n", t.Gets(i, 0), t.Geti(i, 1))
+			}
+			return nil
+		}), nil
+	})
+	if err != nil {
+		t.Errorf("export_test: Error registering format: %s\n", err.Error())
+	}
+	if err := RegisterFormat("Fixed", nil); err == nil {
+		t.Errorf("export_test: Expected error for nil factory\n")
+	}
+	if err := RegisterFormat(FORMATTEXT, func(opts ...interface{}) (Exporter, error) { return nil, nil }); err == nil {
+		t.Errorf("export_test: Expected error for duplicate format\n")
+	}
+
+	got.Reset()
+	if err := tbl.Export(&got, "fixed"); err != nil {
+		t.Errorf("export_test: Error exporting fixed: %s\n", err.Error())
+	}
+	if got.String() != "apple   0042\n" {
+		t.Errorf("export_test: Expected fixed width output, but found %q\n", got.String())
+	}
+
+	var found bool
+	for _, name := range Formats() {
+		if name == "fixed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("export_test: Expected fixed in formats %v\n", Formats())
+	}
+}
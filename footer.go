@@ -0,0 +1,80 @@
+package gotable
+
+// AddFooterRow appends a new row to the footer of the table. Footer rows are
+// printed after the data rows, e.g. for totals, they are not data rows so they
+// are never sorted, counted by RowCount or summed. Without data rows they are
+// printed under the headers and the no rows message.
+func (t *Table) AddFooterRow() {
+	var c Colset
+	t.createColSet(&c)
+	t.Footer = append(t.Footer, c)
+}
+
+// FooterRowCount returns the number of footer rows
+func (t *Table) FooterRowCount() int {
+	return len(t.Footer)
+}
+
+// PutFooter places Cell c at location row,col of the footer, if row < 0 then
+// row is set to the last footer row. If row or col is out of bounds the return
+// value is false. Otherwise, the return value is true.
+func (t *Table) PutFooter(row, col int, c Cell) bool {
+	if row < 0 {
+		row = len(t.Footer) - 1
+	}
+	if row < 0 || row >= len(t.Footer) || col < 0 || col >= len(t.ColDefs) {
+		return false
+	}
+	t.Footer[row].Col[col] = c
+	return true
+}
+
+// PutsFooter updates the cell at row,col of the footer with the string value v,
+// e.g. a label for the totals
+func (t *Table) PutsFooter(row, col int, v string) bool {
+	return t.PutFooter(row, col, Cell{Type: CELLSTRING, Sval: standardizeSpaces(v)})
+}
+
+// GetFooter returns the cell at the supplied row,col of the footer as it is
+// printed, i.e. with the aggregate value if the cell has one. If the supplied
+// row or col is outside the footer's boundaries, then an empty cell is returned.
+func (t *Table) GetFooter(row, col int) Cell {
+	if row < 0 || row >= len(t.Footer) || col < 0 || col >= len(t.ColDefs) {
+		return Cell{}
+	}
+	return t.getFooterRow(row).Col[col]
+}
+
+// SetFooterAggregate shows the aggregate, e.g. AggSum, of all the data rows of
+// column col in the first footer row, which is added if there is no footer.
+// The aggregate is computed when the table is printed. A nil agg removes the
+// aggregate of the column.
+func (t *Table) SetFooterAggregate(col int, agg AggFunc) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	if agg == nil {
+		delete(t.footerAggs, col)
+		return nil
+	}
+	if len(t.Footer) == 0 {
+		t.AddFooterRow()
+	}
+	if t.footerAggs == nil {
+		t.footerAggs = make(map[int]AggFunc)
+	}
+	t.footerAggs[col] = agg
+	return nil
+}
+
+// getFooterRow returns a footer row as it is printed, aggregates are computed
+// over the data rows and placed in the first footer row
+func (t *Table) getFooterRow(row int) Colset {
+	c := Colset{Col: append([]Cell(nil), t.Footer[row].Col...), Height: t.Footer[row].Height}
+	if row == 0 {
+		for col, agg := range t.footerAggs {
+			c.Col[col] = t.aggregate(col, t.getColumnCells(col, 0, len(t.Row)-1), agg)
+		}
+	}
+	return c
+}
@@ -0,0 +1,213 @@
+package gotable
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFooter(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Footer Table")
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Date", 10, CELLDATE, COLJUSTIFYLEFT)
+	for i, name := range []string{"b", "a", "c"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, name)
+		tbl.Puti(-1, 1, int64(i+1))
+		tbl.Putf(-1, 2, float64(i)+0.5)
+		tbl.Putd(-1, 3, time.Date(2017, time.March, 3-i, 0, 0, 0, 0, time.UTC))
+	}
+
+	if err := tbl.SetFooterAggregate(4, AggSum); err == nil {
+		t.Errorf("footer_test: Expected error for invalid column\n")
+	}
+	tbl.SetFooterAggregate(1, AggSum)
+	tbl.SetFooterAggregate(2, AggAvg)
+	tbl.SetFooterAggregate(3, AggMin)
+	tbl.PutsFooter(0, 0, "Total")
+	tbl.AddFooterRow()
+	tbl.PutsFooter(-1, 0, "Rows")
+	tbl.PutFooter(-1, 1, AggCount.Aggregate(tbl.getColumnCells(0, 0, tbl.RowCount()-1)))
+
+	// footer is not data
+	tbl.Sort(0, tbl.RowCount()-1, 0)
+	if tbl.RowCount() != 3 || tbl.FooterRowCount() != 2 {
+		t.Errorf("footer_test: Expected 3 rows and 2 footer rows, found %d and %d\n", tbl.RowCount(), tbl.FooterRowCount())
+	}
+	if c := tbl.Sum(1); c.Ival != 6 {
+		t.Errorf("footer_test: Expected sum 6, found %d\n", c.Ival)
+	}
+	if c := tbl.GetFooter(0, 2); c.Type != CELLFLOAT || c.Fval != 1.5 {
+		t.Errorf("footer_test: Expected average 1.5, found %#v\n", c)
+	}
+	if c := tbl.GetFooter(0, 3); !c.Dval.Equal(time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("footer_test: Expected earliest date, found %#v\n", c)
+	}
+
+	// text: footer after a separator line
+	var temp bytes.Buffer
+	if err := tbl.TextprintTable(&temp); err != nil {
+		t.Errorf("footer_test: Error creating TEXT output: %s\n", err.Error())
+	}
+	expected := "c               3      2.50  03/01/2017\n" +
+		"----------  -----  --------  ----------\n" +
+		"Total           6      1.50  03/01/2017\n" +
+		"Rows            3                      \n"
+	if !strings.HasSuffix(temp.String(), expected) {
+		t.Errorf("footer_test: Expected text output to end with:\n%s\nbut found:\n%s\n", expected, temp.String())
+	}
+
+	// html: tfoot after tbody
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("footer_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := strings.Join(strings.Fields(temp.String()), " ")
+	for _, e := range []string{
+		`</tbody> <tfoot> <tr> <td class="col-0"> Total </td> <td class="col-1"> 6 </td>`,
+		`table tfoot{display:table-footer-group}`,
+		`table tfoot tr td.col-1{text-align:right;}`,
+	} {
+		if !strings.Contains(s, e) {
+			t.Errorf("footer_test: Expected %q in html output, but found:\n%s\n", e, temp.String())
+		}
+	}
+
+	// csv: footer rows are the final rows
+	temp.Reset()
+	if err := tbl.CSVprintTable(&temp); err != nil {
+		t.Errorf("footer_test: Error creating CSV output: %s\n", err.Error())
+	}
+	if !strings.HasSuffix(temp.String(), "\"#footer\"\n\"Total\",    6,    1.50,03/01/2017\n\"Rows\",    3,        ,          \n") {
+		t.Errorf("footer_test: Expected footer rows at the end of csv output, but found:\n%s\n", temp.String())
+	}
+
+	// csv: a reader tells the footer rows by the record before them
+	r := csv.NewReader(bytes.NewReader(temp.Bytes()))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("footer_test: Error reading CSV output: %s\n", err.Error())
+	}
+	var data, footer [][]string
+	for i, rec := range records {
+		if len(rec) == 1 && rec[0] == CSVFOOTER {
+			data, footer = records[:i], records[i+1:]
+		}
+	}
+	if len(footer) != 2 || strings.TrimSpace(footer[1][1]) != "3" || data[len(data)-1][0] != "c" {
+		t.Errorf("footer_test: Expected 2 footer records after the data, found %v\n", footer)
+	}
+
+	// json: footer rows are flagged by their own key
+	temp.Reset()
+	if err := tbl.JSONprintTableLayout(&temp, JSONROWARRAY); err != nil {
+		t.Errorf("footer_test: Error creating JSON output: %s\n", err.Error())
+	}
+	var out struct {
+		Rows   [][]interface{} `json:"rows"`
+		Footer [][]interface{} `json:"footer"`
+	}
+	if err := json.Unmarshal(temp.Bytes(), &out); err != nil {
+		t.Fatalf("footer_test: Error decoding JSON output: %s\n", err.Error())
+	}
+	if len(out.Rows) != 3 || len(out.Footer) != 2 || out.Footer[0][0] != "Total" || out.Footer[0][1] != float64(6) {
+		t.Errorf("footer_test: Expected 3 rows and footer, found:\n%s\n", temp.String())
+	}
+
+	// pdf: footer is repeated on each page
+	for i := 0; i < 150; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "more")
+	}
+	temp.Reset()
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Fatalf("footer_test: Error creating PDF output: %s\n", err.Error())
+	}
+	var pages, totals int
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(temp.String(), -1) {
+		zr, err := zlib.NewReader(strings.NewReader(m[1]))
+		if err != nil {
+			t.Fatalf("footer_test: Error inflating page content: %s\n", err.Error())
+		}
+		b, _ := ioutil.ReadAll(zr)
+		pages++
+		totals += strings.Count(string(b), "(Total) Tj")
+	}
+	if pages < 2 || totals != pages {
+		t.Errorf("footer_test: Expected footer on each of %d pages, found %d\n", pages, totals)
+	}
+
+	// footer and built-in aggregates survive json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("footer_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("footer_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if tbl2.FooterRowCount() != 2 || tbl2.GetFooter(0, 1).Ival != 6 || tbl2.GetFooter(0, 2).Fval != tbl.GetFooter(0, 2).Fval {
+		t.Errorf("footer_test: Expected footer to be preserved, found %v\n", tbl2.Footer)
+	}
+}
+
+func TestFooterNoRows(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.SetFooterAggregate(1, AggCount)
+	tbl.PutsFooter(0, 0, "Rows")
+
+	// footer is shown under the headers even without rows
+	for _, c := range []struct {
+		format string
+		footer string
+	}{
+		{FORMATTEXT, "Rows            0\n"},
+		{FORMATCSV, "\"Name\",\"Count\"\n\"" + tbl.HasData().Error() + "\"\n\"#footer\"\n\"Rows\",    0\n"},
+		{FORMATHTML, "<tfoot> <tr> <td class=\"col-0\"> Rows </td> <td class=\"col-1\"> 0 </td> </tr> </tfoot>"},
+		{FORMATJSON, "\"footer\": [\n    {\n      \"Name\": \"Rows\",\n      \"Count\": 0\n    }\n  ]"},
+		{FORMATMARKDOWN, "| **Rows** | **0** |"},
+	} {
+		var temp bytes.Buffer
+		if err := tbl.Export(&temp, c.format); err != nil {
+			t.Errorf("footer_test: Error creating %s output: %s\n", c.format, err.Error())
+		}
+		s := temp.String()
+		if c.format == FORMATHTML {
+			s = strings.Join(strings.Fields(s), " ")
+		}
+		if !strings.Contains(s, c.footer) || !strings.Contains(s, tbl.HasData().Error()) {
+			t.Errorf("footer_test: Expected %q in %s output without rows, found:\n%s\n", c.footer, c.format, temp.String())
+		}
+	}
+
+	var temp bytes.Buffer
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Fatalf("footer_test: Error creating PDF output: %s\n", err.Error())
+	}
+	var totals int
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(temp.String(), -1) {
+		zr, err := zlib.NewReader(strings.NewReader(m[1]))
+		if err != nil {
+			t.Fatalf("footer_test: Error inflating page content: %s\n", err.Error())
+		}
+		b, _ := ioutil.ReadAll(zr)
+		totals += strings.Count(string(b), "(Rows) Tj")
+	}
+	if totals != 1 {
+		t.Errorf("footer_test: Expected footer in pdf output without rows, found %d\n", totals)
+	}
+}
@@ -2,12 +2,17 @@ package gotable
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/dustin/go-humanize"
 )
 
 // Table is a simple skeletal row-column "class" for go that implements a few
@@ -33,6 +38,9 @@ const (
 	TABLEOUTHTML = 2
 	TABLEOUTPDF  = 3
 	TABLEOUTCSV  = 4
+	TABLEOUTMD   = 5
+	TABLEOUTJSON = 6
+	TABLEOUTXLSX = 7
 
 	CSSFONTSIZE = 14
 	NEWLINE     = "\n"
@@ -45,6 +53,7 @@ type Cell struct {
 	Fval float64   // float value
 	Sval string    // string value
 	Dval time.Time // datetime value
+	HTML bool      `json:",omitempty"` // Sval holds trusted html, it is not escaped in html output
 }
 
 // ColumnDef defines a Table column -- a column title, justification, and formatting
@@ -58,12 +67,15 @@ type ColumnDef struct {
 	Hdr       []string // multiple lines of column headers as needed -- based on width and Title
 	Fdecimals int      // the number of decimal digits for floating point numbers. The default is 2
 	HTMLWidth int
+	Key       string   `json:",omitempty"` // name of the column used by the ...ByKey methods, see AddColumnWithKey
+	Hidden    []string `json:",omitempty"` // export formats in which the column is hidden, see SetColumnHidden
 }
 
 // Colset defines a set of Cells
 type Colset struct {
 	Col    []Cell // 1 row's worth of Cells, contains len(Col) number of Cells
 	Height int    // height of row
+	cssSeq int    // css sequence number when the row was added, css set later applies to it
 }
 
 // Rowset defines a set of rows to be operated on at a later time.
@@ -79,16 +91,29 @@ type Table struct {
 	Section2        string                             // a third section for the title, in a different style
 	Section3        string                             // another section for extra usage
 	ColDefs         []ColumnDef                        // table's column definitions, ordered 0..n left to right
+	HeaderGroups    []HeaderGroup                      // titles spanning column headers, see AddHeaderGroup
 	Row             []Colset                           // Each Colset forms a row
+	Footer          []Colset                           // footer rows, printed after the rows, see AddFooterRow
 	maxHdrRows      int                                // maximum number of header rows across all ColDefs
 	DateFmt         string                             // format for printing dates
 	DateTimeFmt     string                             // format for datetime values
 	LineAfter       []int                              // array of row numbers that have a horizontal line after they are printed
 	LineBefore      []int                              // array of row numbers that have a horizontal line before they are printed
 	RS              []Rowset                           // a list of rowsets
+	Merges          []CellMerge                        // merged cells, see MergeCells
 	CSS             map[string]map[string]*CSSProperty //CSS holds css property for title, section1, section2, headers, cells
 	htmlTemplate    string                             // path of custom html template path
 	htmlTemplateCSS string                             // path of custom css for html template
+	theme           *Theme                             // look of html and pdf output
+	htmlInteractive *HTMLInteractiveOptions            // interactive html options, nil if not interactive
+	csvMergeRepeat  bool                               // repeat the value of merged cells in csv output
+	footerAggs      map[int]AggFunc                    // aggregates shown in the first footer row, by column
+	cssSeq          int                                // sequence number of the last cell css set, see setCSS
+	// html template and css read from a file system, and template parsed by caller
+	htmlTemplateFS     fs.FS
+	htmlTemplateFSName string
+	htmlTemplateFSCSS  string
+	htmlTemplateParsed *template.Template
 	// errorList       []string                           // stores the list of error in string format
 }
 
@@ -190,6 +215,23 @@ func (t *Table) ColCount() int {
 // 	return "unknown"
 // }
 
+// cellTypeName returns a string describing the cell data type
+func cellTypeName(cellType int) string {
+	switch cellType {
+	case CELLSTRING:
+		return "string"
+	case CELLINT:
+		return "int"
+	case CELLFLOAT:
+		return "float"
+	case CELLDATE:
+		return "date"
+	case CELLDATETIME:
+		return "datetime"
+	}
+	return "unknown"
+}
+
 // Init sets internal formatting controls to their default values
 func (t *Table) Init() {
 	t.DateFmt = "01/02/2006"
@@ -219,6 +261,39 @@ func (t *Table) SetHTMLTemplateCSS(path string) error {
 	return nil
 }
 
+// SetHTMLTemplateFS sets the html template and css to be read from fsys, e.g.
+// an embed.FS of the caller. A blank name keeps the default for that part.
+// It takes precedence over SetHTMLTemplate and SetHTMLTemplateCSS.
+// The file system is not kept in the json encoding of the table.
+func (t *Table) SetHTMLTemplateFS(fsys fs.FS, tmplName, cssName string) error {
+	if fsys == nil {
+		return fmt.Errorf("Provided file system is nil")
+	}
+	if cssName != "" {
+		if _, err := fs.Stat(fsys, cssName); err != nil {
+			return fmt.Errorf("Provided css %s is not valid: %s", cssName, err.Error())
+		}
+	}
+	if tmplName != "" {
+		if _, err := template.ParseFS(fsys, tmplName); err != nil {
+			return fmt.Errorf("Provided template %s is not valid: %s", tmplName, err.Error())
+		}
+	}
+
+	t.htmlTemplateFS = fsys
+	t.htmlTemplateFSName = tmplName
+	t.htmlTemplateFSCSS = cssName
+	return nil
+}
+
+// SetHTMLTemplateParsed sets a text/template parsed by the caller, e.g. with a
+// custom FuncMap, to be executed with HTMLTemplateContext. It takes precedence
+// over other template settings, a nil template removes it. It is not kept in
+// the json encoding of the table.
+func (t *Table) SetHTMLTemplateParsed(tmpl *template.Template) {
+	t.htmlTemplateParsed = tmpl
+}
+
 // AddLineAfter keeps track of the row numbers after which a line will be printed
 func (t *Table) AddLineAfter(row int) {
 	t.LineAfter = append(t.LineAfter, row)
@@ -231,6 +306,18 @@ func (t *Table) AddLineBefore(row int) {
 	sort.Ints(t.LineBefore)
 }
 
+// hasLineAfter reports whether a line is printed after the given row
+func (t *Table) hasLineAfter(row int) bool {
+	j := sort.SearchInts(t.LineAfter, row)
+	return j < len(t.LineAfter) && t.LineAfter[j] == row
+}
+
+// hasLineBefore reports whether a line is printed before the given row
+func (t *Table) hasLineBefore(row int) bool {
+	j := sort.SearchInts(t.LineBefore, row)
+	return j < len(t.LineBefore) && t.LineBefore[j] == row
+}
+
 // CreateRowset creates a new rowset. You can add row indeces to it.  You can process the rows at those indeces later.
 // The return value is the Rowset identifier; rsid.  Use it to refer to this rowset.
 func (t *Table) CreateRowset() int {
@@ -286,6 +373,15 @@ func (t *Table) AdjustFormatString(cd *ColumnDef) {
 	}
 }
 
+// floatText returns f formatted by Pfmt. Unless Pfmt is a float format, e.g.
+// "%12.3f", f is humanized with up to 2 decimals first.
+func (cd *ColumnDef) floatText(f float64) string {
+	if strings.HasSuffix(cd.Pfmt, "s") {
+		return fmt.Sprintf(cd.Pfmt, humanize.FormatFloat("#,###.##", f))
+	}
+	return fmt.Sprintf(cd.Pfmt, f)
+}
+
 // AddColumn adds a new ColumnDef to the table
 func (t *Table) AddColumn(title string, width, celltype int, justification int) {
 	var cd = ColumnDef{
@@ -404,6 +500,24 @@ func (t *Table) Type(row, col int) int {
 	return t.Row[row].Col[col].Type
 }
 
+// cellText returns the value of a cell formatted for column col, without
+// any padding
+func (t *Table) cellText(c Cell, col int) string {
+	switch c.Type {
+	case CELLFLOAT:
+		return strings.TrimSpace(t.ColDefs[col].floatText(c.Fval))
+	case CELLINT:
+		return strings.TrimSpace(fmt.Sprintf(t.ColDefs[col].Pfmt, c.Ival))
+	case CELLSTRING:
+		return c.Sval
+	case CELLDATE:
+		return c.Dval.Format(t.DateFmt)
+	case CELLDATETIME:
+		return c.Dval.Format(t.DateTimeFmt)
+	}
+	return ""
+}
+
 // Puti updates the Cell at row,col with the int64 value v
 // and sets its type to CELLINT. If row or col is out of
 // bounds the return value is false. Otherwise, the return
@@ -451,6 +565,7 @@ func (t *Table) Puts(row, col int, v string) bool {
 	}
 	t.Row[row].Col[col].Type = CELLSTRING
 	t.Row[row].Col[col].Sval = standardizeSpaces(v)
+	t.Row[row].Col[col].HTML = false
 
 	// Need to check width of column everytime when we adding new content
 	// if it is updatable or not
@@ -465,6 +580,20 @@ func (t *Table) Puts(row, col int, v string) bool {
 	return true
 }
 
+// PutHTML works like Puts, but v is a trusted html fragment, e.g. a link or
+// an icon, which html output writes as it is. Other outputs treat v as plain
+// text. Values stored by Puts are always escaped in html output.
+func (t *Table) PutHTML(row, col int, v string) bool {
+	if !t.Puts(row, col, v) {
+		return false
+	}
+	if row < 0 {
+		row = len(t.Row) - 1
+	}
+	t.Row[row].Col[col].HTML = true
+	return true
+}
+
 // Putd updates the Cell at row,col with the date value v
 // and sets its type to CELLDATE. If row or col is out of
 // bounds the return value is false. Otherwise, the return
@@ -508,6 +637,7 @@ func (t *Table) createColSet(c *Colset) {
 		c.Col = append(c.Col, cell)
 	}
 	c.Height = 1
+	c.cssSeq = t.cssSeq
 }
 
 // Sum computes the sum of the rows at the specified column index. It returns a Cell
@@ -547,7 +677,8 @@ func (t *Table) InsertSumRow(row, from, to int, cols []int) {
 	}
 }
 
-// Sort sorts rows (from,to) by column col ascending
+// Sort sorts rows (from,to) by column col ascending. SortBy sorts by several
+// columns, in either direction, and is much faster for large tables.
 func (t *Table) Sort(from, to, col int) {
 	// fmt.Printf("Table.Sort:  from = %d, to = %d, col = %d,  len(t.Row) = %d\n", from, to, col, len(t.Row))
 	var swap bool
@@ -570,6 +701,48 @@ func (t *Table) Sort(from, to, col int) {
 	}
 }
 
+// compareCells returns -1, 0 or 1 if a sorts before, same as or after b.
+// Numbers, dates and strings are compared by their values, strings ignore
+// case first. Cells of different kinds are ordered by their type, empty
+// cells first.
+func compareCells(a, b Cell) int {
+	switch {
+	case a.isNumber() && b.isNumber():
+		if a.Type == CELLINT && b.Type == CELLINT {
+			return compareInts(a.Ival, b.Ival)
+		}
+		return compareFloats(a.float(), b.float())
+	case a.isDate() && b.isDate():
+		return compareInts(a.Dval.UnixNano(), b.Dval.UnixNano())
+	case a.Type == CELLSTRING && b.Type == CELLSTRING:
+		if c := strings.Compare(strings.ToLower(a.Sval), strings.ToLower(b.Sval)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Sval, b.Sval)
+	}
+	return compareInts(int64(a.Type), int64(b.Type))
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
 // InsertSumRowsetCols sums the values for the specified rowset and appends it at the specified row
 // rsid = the RowSet on which to perform the sum
 // row  = a row will be inserted at this index, and the totals will be added to this row
@@ -600,6 +773,7 @@ func (t *Table) InsertRow(row int) {
 	t.createColSet(&c)
 	t.Row = append(t.Row[:row+1], t.Row[row:]...)
 	t.Row[row] = c
+	t.adjustMergesInsertRow(row)
 
 	// Adjust LineAfter
 	for i := 0; i < len(t.LineAfter); i++ {
@@ -620,10 +794,11 @@ func (t *Table) InsertRow(row int) {
 	}
 }
 
-// DeleteRow removes the table row at the specified index. All rowsets and LineAfter sets are adjusted.
+// DeleteRow removes the table row at the specified index. All rowsets, merges and LineAfter sets are adjusted.
 // Cleanup on LineAfter and RowSets does not work if row == 0. I was just too lazy at the time to add this
 // code because I know how/where delete will be used and it will not affect row 0.
 func (t *Table) DeleteRow(row int) {
+	t.adjustMergesDeleteRow(row)
 	t.Row = t.Row[:row+copy(t.Row[row:], t.Row[row+1:])] // this removes t.Row[row]
 	// Clean up LineAfter
 	for i := 0; i < len(t.LineAfter); i++ {
@@ -651,6 +826,72 @@ func (t *Table) DeleteRow(row int) {
 	}
 }
 
+// reorderRows replaces the rows of the table with rows, from[i] is the current
+// index of rows[i] or -1 for a new row. Separator lines, rowsets, merges and
+// css of rows and cells follow the rows, merges which are split apart and
+// anything which belongs to a row not in rows are removed.
+func (t *Table) reorderRows(rows []Colset, from []int) {
+	to := make(map[int]int, len(from))
+	for i, r := range from {
+		if r >= 0 {
+			to[r] = i
+		}
+	}
+	remap := func(a []int) []int {
+		var b []int
+		for _, r := range a {
+			if i, ok := to[r]; ok {
+				b = append(b, i)
+			}
+		}
+		return b
+	}
+
+	t.LineAfter = remap(t.LineAfter)
+	sort.Ints(t.LineAfter)
+	t.LineBefore = remap(t.LineBefore)
+	sort.Ints(t.LineBefore)
+	for i := 0; i < len(t.RS); i++ {
+		t.RS[i].R = remap(t.RS[i].R)
+	}
+
+	var merges []CellMerge
+	for _, m := range t.Merges {
+		top, keep := to[m.Row]
+		for j := 1; keep && j < m.RowSpan; j++ {
+			i, ok := to[m.Row+j]
+			keep = ok && i == top+j
+		}
+		if keep {
+			m.Row = top
+			merges = append(merges, m)
+		}
+	}
+	t.Merges = merges
+
+	// css keys of rows and cells hold the row index
+	css := make(map[string]map[string]*CSSProperty, len(t.CSS))
+	for k, v := range t.CSS {
+		var r, c int
+		if n, _ := fmt.Sscanf(k, "row-%d", &r); n == 1 && k == t.getCSSMapKeyForRow(r) {
+			if i, ok := to[r]; ok {
+				css[t.getCSSMapKeyForRow(i)] = v
+			}
+			continue
+		}
+		if n, _ := fmt.Sscanf(k, "row:%d-col:%d", &r, &c); n == 2 && k == t.getCSSMapKeyForCell(r, c) {
+			if i, ok := to[r]; ok {
+				css[t.getCSSMapKeyForCell(i, c)] = v
+			}
+			continue
+		}
+		css[k] = v
+	}
+	t.CSS = css
+
+	t.Row = rows
+}
+
 // TightenColumns goes through all values in STRING columns and determines the maximum length in characters (max).
 // If this length is less than the column width the column width is reduced to max.  This is
 // mostly useful for text formatting.
@@ -762,7 +1003,7 @@ func (t *Table) SprintTable() (string, error) {
 
 // FprintTable renders the entire table for io.Writer object for text output
 func (t *Table) FprintTable(w io.Writer) error {
-	var tout TableExportType = &TextTable{Table: t, TextColSpace: 2}
+	var tout TableExportType = &TextTable{Table: t.visible(FORMATTEXT), TextColSpace: 2}
 	return tout.writeTableOutput(w)
 }
 
@@ -773,19 +1014,92 @@ func (t *Table) TextprintTable(w io.Writer) error {
 
 // CSVprintTable renders the entire table for csv output
 func (t *Table) CSVprintTable(w io.Writer) error {
-	var tout TableExportType = &CSVTable{Table: t, CellSep: ","}
+	var tout TableExportType = &CSVTable{Table: t.visible(FORMATCSV), CellSep: ","}
 	return tout.writeTableOutput(w)
 }
 
 // HTMLprintTable renders the entire table for html output
 func (t *Table) HTMLprintTable(w io.Writer) error {
-	var tout TableExportType = &HTMLTable{Table: t}
+	var tout TableExportType = &HTMLTable{Table: t.visible(FORMATHTML)}
+	return tout.writeTableOutput(w)
+}
+
+// HTMLFragmentprintTable renders the table as an html fragment to embed in
+// another page: the table container div preceded by its <style> block, without
+// the page template. id is set on the container and scopes all css rules, so
+// tables with different ids don't clash on one page. id must be a valid css
+// identifier, with a blank id the css is not scoped.
+func (t *Table) HTMLFragmentprintTable(w io.Writer, id string) error {
+	var tout TableExportType = &HTMLTable{Table: t.visible(FORMATFRAGMENT), fragment: true, containerID: id}
+	return tout.writeTableOutput(w)
+}
+
+// HTMLFragment works like HTMLFragmentprintTable, but returns the container
+// html and its stylesheet separately, e.g. to put the stylesheet in the head of
+// the page
+func (t *Table) HTMLFragment(id string) (html, css string, err error) {
+	ht := &HTMLTable{Table: t.visible(FORMATFRAGMENT), fragment: true, containerID: id}
+	return ht.getFragment()
+}
+
+// MarkdownprintTable renders the entire table for markdown (GitHub-flavored) output
+func (t *Table) MarkdownprintTable(w io.Writer) error {
+	var tout TableExportType = &MarkdownTable{Table: t.visible(FORMATMARKDOWN)}
+	return tout.writeTableOutput(w)
+}
+
+// JSONprintTable renders the entire table for json output, each row is
+// rendered as an object keyed by column key or title
+func (t *Table) JSONprintTable(w io.Writer) error {
+	return t.JSONprintTableLayout(w, JSONROWOBJECT)
+}
+
+// JSONprintTableLayout renders the entire table for json output with the
+// given row layout, JSONROWOBJECT or JSONROWARRAY
+func (t *Table) JSONprintTableLayout(w io.Writer, layout int) error {
+	if err := checkJSONLayout(layout); err != nil {
+		return err
+	}
+	var tout TableExportType = &JSONTable{Table: t.visible(FORMATJSON), RowLayout: layout}
 	return tout.writeTableOutput(w)
 }
 
-// PDFprintTable renders the entire table for pdf output
+// XLSXprintTable renders the entire table for xlsx (Excel workbook) output
+func (t *Table) XLSXprintTable(w io.Writer) error {
+	var tout TableExportType = &XLSXTable{Table: t.visible(FORMATXLSX)}
+	return tout.writeTableOutput(w)
+}
+
+// PDFprintTable renders the entire table for pdf output with the native engine
 func (t *Table) PDFprintTable(w io.Writer) error {
-	var tout = &PDFTable{Table: t}
+	return t.PDFprintTableWithOptions(w, DefaultPDFOptions())
+}
+
+// PDFprintTableEngine renders the entire table for pdf output with the given
+// engine, PDFENGINENATIVE or PDFENGINEWKHTMLTOPDF
+func (t *Table) PDFprintTableEngine(w io.Writer, engine int) error {
+	opts := DefaultPDFOptions()
+	opts.Engine = engine
+	return t.PDFprintTableWithOptions(w, opts)
+}
+
+// PDFprintTableWithOptions renders the entire table for pdf output with the
+// given page setup, header/footer and metadata options
+func (t *Table) PDFprintTableWithOptions(w io.Writer, opts PDFOptions) error {
+	return t.PDFprintTableWithOptionsContext(context.Background(), w, opts)
+}
+
+// PDFprintTableContext renders the entire table for pdf output with the native
+// engine. Rendering is abandoned with ctx.Err() as soon as ctx is done
+func (t *Table) PDFprintTableContext(ctx context.Context, w io.Writer) error {
+	return t.PDFprintTableWithOptionsContext(ctx, w, DefaultPDFOptions())
+}
+
+// PDFprintTableWithOptionsContext renders the entire table for pdf output with
+// the given options. Rendering is abandoned with ctx.Err() as soon as ctx is
+// done, the wkhtmltopdf process is killed in that case
+func (t *Table) PDFprintTableWithOptionsContext(ctx context.Context, w io.Writer, opts PDFOptions) error {
+	var tout = &PDFTable{Table: t.visible(FORMATPDF), Options: opts, ctx: ctx}
 	return tout.writeTableOutput(w)
 }
 
@@ -796,6 +1110,7 @@ func (t *Table) PDFprintTable(w io.Writer) error {
 // CSSProperty holds css property to be used as inline css
 type CSSProperty struct {
 	Name, Value string
+	seq         int // order in which cell css was set, see setCSS
 }
 
 // String is the "stringer" method implementation for CSSProperty
@@ -808,6 +1123,40 @@ func (t *Table) getCSSMapKeyForCell(rowIndex, colIndex int) string {
 	return `row:` + strconv.Itoa(rowIndex) + `-col:` + strconv.Itoa(colIndex)
 }
 
+// getCSSMapKeyForRow format and returns key for a row for css properties usage,
+// it is also the class of the row in html output
+func (t *Table) getCSSMapKeyForRow(rowIndex int) string {
+	return `row-` + strconv.Itoa(rowIndex)
+}
+
+// getCSSMapKeyForCol format and returns key for a column for css properties usage,
+// it is also the class of the cells of the column in html output
+func (t *Table) getCSSMapKeyForCol(colIndex int) string {
+	return `col-` + strconv.Itoa(colIndex)
+}
+
+// setCSS merges cssList into the cell css properties stored under key, i.e.
+// css of all cells, rows, columns or single cells. Each call gets the next
+// sequence number, html output applies the properties in that order, so the
+// last call wins, and only to the rows which exist already. The map is
+// replaced rather than changed, a copy of the table may share it.
+func (t *Table) setCSS(key string, cssList []*CSSProperty) {
+	t.cssSeq++
+
+	// css property map
+	cssMap := make(map[string]*CSSProperty, len(t.CSS[key])+len(cssList))
+	for name, cssProp := range t.CSS[key] {
+		cssMap[name] = cssProp
+	}
+
+	// map it in style of html table
+	for _, cssProp := range cssList {
+		cssMap[cssProp.Name] = &CSSProperty{Name: cssProp.Name, Value: cssProp.Value, seq: t.cssSeq}
+	}
+
+	t.CSS[key] = cssMap
+}
+
 // getCSSMapKeyForHeaderCell format and returns key for eader cell for css properties usage
 func (t *Table) getCSSMapKeyForHeaderCell(colIndex int) string {
 	return `header-` + strconv.Itoa(colIndex)
@@ -955,36 +1304,21 @@ func (t *Table) SetCellCSS(rowIndex, colIndex int, cssList []*CSSProperty) error
 		return err
 	}
 
-	// css property map
-	g := t.getCSSMapKeyForCell(rowIndex, colIndex)
-	cssMap, ok := t.CSS[g]
-	if !ok {
-		cssMap = make(map[string]*CSSProperty)
-	}
-
-	// map it in style of html table
-	for _, cssProp := range cssList {
-		cssMap[cssProp.Name] = cssProp
-	}
-
-	t.CSS[g] = cssMap
-
+	t.setCSS(t.getCSSMapKeyForCell(rowIndex, colIndex), cssList)
 	return nil
 }
 
-// SetAllCellCSS sets css properties for all Table Cells
+// SetAllCellCSS sets css properties for all Table Cells. In html output it
+// becomes a single rule for the rows which exist already.
+//
+// When the same property is set for a cell more than once, by SetAllCellCSS,
+// SetRowCSS, SetColCSS or SetCellCSS, the last call wins.
 func (t *Table) SetAllCellCSS(cssList []*CSSProperty) {
-
-	// convert it into cells attributes
-	for colIndex := 0; colIndex < t.ColCount(); colIndex++ {
-		for rowIndex := 0; rowIndex < t.RowCount(); rowIndex++ {
-			// will never meet an error from below function
-			t.SetCellCSS(rowIndex, colIndex, cssList)
-		}
-	}
+	t.setCSS(ALLCELLSCLASS, cssList)
 }
 
-// SetRowCSS sets css properties for Table Rows
+// SetRowCSS sets css properties for Table Rows. In html output it becomes
+// a single rule for the row class
 func (t *Table) SetRowCSS(rowIndex int, cssList []*CSSProperty) error {
 
 	// check row is valid or not
@@ -992,16 +1326,12 @@ func (t *Table) SetRowCSS(rowIndex int, cssList []*CSSProperty) error {
 		return err
 	}
 
-	// convert it into cells attributes
-	for colIndex := 0; colIndex < t.ColCount(); colIndex++ {
-		// for valid rowIndex set css for all cells belongs to rowIndex row
-		t.SetCellCSS(rowIndex, colIndex, cssList)
-	}
-
+	t.setCSS(t.getCSSMapKeyForRow(rowIndex), cssList)
 	return nil
 }
 
-// SetColCSS sets css properties for Table Columns
+// SetColCSS sets css properties for Table Columns. In html output it becomes
+// a single rule for the column class of the rows which exist already
 func (t *Table) SetColCSS(colIndex int, cssList []*CSSProperty) error {
 
 	// check row is valid or not
@@ -1009,12 +1339,7 @@ func (t *Table) SetColCSS(colIndex int, cssList []*CSSProperty) error {
 		return err
 	}
 
-	// convert it into cells attributes
-	for rowIndex := 0; rowIndex < t.RowCount(); rowIndex++ {
-		// for valid colIndex set css for all cells belongs to colIndex column
-		t.SetCellCSS(rowIndex, colIndex, cssList)
-	}
-
+	t.setCSS(t.getCSSMapKeyForCol(colIndex), cssList)
 	return nil
 }
 
@@ -0,0 +1,139 @@
+package gotable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupByOptions holds the options of GroupBy. Labels are placed in LabelCol,
+// "%s" in a label is replaced by the key of the group.
+type GroupByOptions struct {
+	Headers         bool   // insert a row holding the label of the group before each group
+	Subtotals       bool   // insert a row holding the aggregates of the group after each group
+	GrandTotal      bool   // append a row holding the aggregates of all the rows
+	LabelCol        int    // column of the labels, it should be a string column
+	HeaderLabel     string // label of header rows, default is "%s"
+	SubtotalLabel   string // label of subtotal rows, default is "Total %s"
+	GrandTotalLabel string // label of the grand total row, default is "Grand Total"
+}
+
+// GroupBy sorts the rows by keyCols and groups the rows with the same keys.
+// Groups are nested, the rows of a group by keyCols[0] are grouped again by
+// keyCols[1] and so on. Depending on opts, a header row is inserted before each
+// group and a subtotal row after each group, and a grand total row is appended
+// after all the rows. The aggregates, by column, are computed over the data rows
+// of the group, never over inserted rows. A line separates the rows of a group
+// from its subtotal and the grand total from the rest.
+func (t *Table) GroupBy(keyCols []int, aggregates map[int]AggFunc, opts GroupByOptions) error {
+	if len(keyCols) == 0 {
+		return fmt.Errorf("No key columns to group by")
+	}
+	for _, col := range keyCols {
+		if err := t.HasValidColumn(col); err != nil {
+			return err
+		}
+	}
+	for col := range aggregates {
+		if err := t.HasValidColumn(col); err != nil {
+			return err
+		}
+	}
+	if err := t.HasValidColumn(opts.LabelCol); err != nil {
+		return err
+	}
+	if opts.HeaderLabel == "" {
+		opts.HeaderLabel = "%s"
+	}
+	if opts.SubtotalLabel == "" {
+		opts.SubtotalLabel = "Total %s"
+	}
+	if opts.GrandTotalLabel == "" {
+		opts.GrandTotalLabel = "Grand Total"
+	}
+
+	// sort the indexes of rows by the keys, equal rows keep their order
+	idx := make([]int, len(t.Row))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		for _, col := range keyCols {
+			if c := compareCells(t.Row[idx[a]].Col[col], t.Row[idx[b]].Col[col]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	var rows []Colset
+	var from, lines []int
+	labels := map[int]string{}
+
+	// addTotal appends a row holding the aggregates of the rows idx
+	addTotal := func(idx []int, label string) {
+		lines = append(lines, len(rows)-1)
+		var c Colset
+		t.createColSet(&c)
+		for col, agg := range aggregates {
+			cells := make([]Cell, len(idx))
+			for i, r := range idx {
+				cells[i] = t.Row[r].Col[col]
+			}
+			c.Col[col] = t.aggregate(col, cells, agg)
+		}
+		labels[len(rows)] = label
+		rows = append(rows, c)
+		from = append(from, -1)
+	}
+
+	var group func(idx []int, level int)
+	group = func(idx []int, level int) {
+		col := keyCols[level]
+		for start := 0; start < len(idx); {
+			end := start + 1
+			for end < len(idx) && compareCells(t.Row[idx[start]].Col[col], t.Row[idx[end]].Col[col]) == 0 {
+				end++
+			}
+			key := t.cellText(t.Row[idx[start]].Col[col], col)
+
+			if opts.Headers {
+				var c Colset
+				t.createColSet(&c)
+				labels[len(rows)] = strings.Replace(opts.HeaderLabel, "%s", key, -1)
+				rows = append(rows, c)
+				from = append(from, -1)
+			}
+			if level+1 < len(keyCols) {
+				group(idx[start:end], level+1)
+			} else {
+				for _, r := range idx[start:end] {
+					rows = append(rows, t.Row[r])
+					from = append(from, r)
+				}
+			}
+			if opts.Subtotals {
+				addTotal(idx[start:end], strings.Replace(opts.SubtotalLabel, "%s", key, -1))
+			}
+			start = end
+		}
+	}
+	group(idx, 0)
+	if opts.GrandTotal && len(idx) > 0 {
+		addTotal(idx, opts.GrandTotalLabel)
+	}
+
+	t.reorderRows(rows, from)
+	for _, row := range lines {
+		if row >= 0 && !t.hasLineAfter(row) {
+			t.AddLineAfter(row)
+		}
+	}
+	for row, label := range labels {
+		// aggregates win over labels
+		if t.Row[row].Col[opts.LabelCol].Type == 0 {
+			t.Puts(row, opts.LabelCol, label)
+		}
+	}
+	return nil
+}
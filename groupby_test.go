@@ -0,0 +1,96 @@
+package gotable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Group Table")
+	tbl.AddColumn("Region", 12, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("City", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 8, CELLINT, COLJUSTIFYRIGHT)
+	for _, r := range []struct {
+		region, city string
+		amount       int64
+	}{{"east", "boston", 10}, {"west", "la", 5}, {"east", "nyc", 20}, {"west", "sf", 7}, {"east", "boston", 1}} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, r.region)
+		tbl.Puts(-1, 1, r.city)
+		tbl.Puti(-1, 2, r.amount)
+	}
+	tbl.SetRowCSS(2, []*CSSProperty{{Name: "color", Value: "red"}})
+
+	sum := map[int]AggFunc{2: AggSum}
+	if err := tbl.GroupBy(nil, sum, GroupByOptions{}); err == nil {
+		t.Errorf("groupby_test: Expected error for no key columns\n")
+	}
+	if err := tbl.GroupBy([]int{3}, sum, GroupByOptions{}); err == nil {
+		t.Errorf("groupby_test: Expected error for invalid key column\n")
+	}
+	if err := tbl.GroupBy([]int{0, 1}, sum, GroupByOptions{Headers: true, Subtotals: true, GrandTotal: true}); err != nil {
+		t.Fatalf("groupby_test: Error grouping table: %s\n", err.Error())
+	}
+
+	expected := []struct {
+		label  string
+		amount int64
+	}{
+		{"east", 0},
+		{"boston", 0},
+		{"east", 10},
+		{"east", 1},
+		{"Total boston", 11},
+		{"nyc", 0},
+		{"east", 20},
+		{"Total nyc", 20},
+		{"Total east", 31},
+		{"west", 0},
+		{"la", 0},
+		{"west", 5},
+		{"Total la", 5},
+		{"sf", 0},
+		{"west", 7},
+		{"Total sf", 7},
+		{"Total west", 12},
+		{"Grand Total", 43},
+	}
+	if tbl.RowCount() != len(expected) {
+		t.Fatalf("groupby_test: Expected %d rows, found %d\n", len(expected), tbl.RowCount())
+	}
+	for i, e := range expected {
+		if tbl.Gets(i, 0) != e.label || tbl.Geti(i, 2) != e.amount {
+			t.Errorf("groupby_test: Expected row %d to be %q %d, found %q %d\n", i, e.label, e.amount, tbl.Gets(i, 0), tbl.Geti(i, 2))
+		}
+	}
+
+	// lines separate the rows of each group from its subtotal
+	if lines := []int{3, 6, 7, 11, 14, 15, 16}; !reflect.DeepEqual(tbl.LineAfter, lines) {
+		t.Errorf("groupby_test: Expected lines after rows %v, found %v\n", lines, tbl.LineAfter)
+	}
+
+	// row css moves with its row, "east nyc 20" was row 2
+	if _, ok := tbl.CSS["row-6"]; !ok || len(tbl.CSS) != 1 {
+		t.Errorf("groupby_test: Expected row css to move to row 6, found %v\n", tbl.CSS)
+	}
+
+	// subtotals only, with custom labels
+	tbl = Table{}
+	tbl.Init()
+	tbl.AddColumn("Region", 12, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 8, CELLINT, COLJUSTIFYRIGHT)
+	for i, region := range []string{"west", "east", "west"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, region)
+		tbl.Puti(-1, 1, int64(i+1))
+	}
+	opts := GroupByOptions{Subtotals: true, SubtotalLabel: "%s subtotal"}
+	if err := tbl.GroupBy([]int{0}, map[int]AggFunc{1: AggCount}, opts); err != nil {
+		t.Fatalf("groupby_test: Error grouping table: %s\n", err.Error())
+	}
+	if tbl.RowCount() != 5 || tbl.Gets(1, 0) != "east subtotal" || tbl.Geti(1, 1) != 1 || tbl.Gets(4, 0) != "west subtotal" || tbl.Geti(4, 1) != 2 {
+		t.Errorf("groupby_test: Unexpected subtotals, found rows %v\n", tbl.Row)
+	}
+}
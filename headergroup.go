@@ -0,0 +1,114 @@
+package gotable
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HeaderGroup is a title spanning the headers of columns FromCol thru ToCol.
+// Groups can be nested, a group is shown above all the groups it contains,
+// e.g. "2017" above "Q1" and "Q2" which are above "Revenue" and "Cost".
+type HeaderGroup struct {
+	Title   string `json:"title"`
+	FromCol int    `json:"fromCol"` // first column of the group
+	ToCol   int    `json:"toCol"`   // last column of the group
+}
+
+// contains reports whether the group covers all columns of o
+func (g HeaderGroup) contains(o HeaderGroup) bool {
+	return g.FromCol <= o.FromCol && o.ToCol <= g.ToCol
+}
+
+// overlaps reports whether two groups cover a common column
+func (g HeaderGroup) overlaps(o HeaderGroup) bool {
+	return g.FromCol <= o.ToCol && o.FromCol <= g.ToCol
+}
+
+// AddHeaderGroup adds a title spanning the headers of columns fromCol thru
+// toCol. Groups which cover common columns must be nested, the order in which
+// they are added does not matter.
+func (t *Table) AddHeaderGroup(title string, fromCol, toCol int) error {
+	for _, c := range []int{fromCol, toCol} {
+		if err := t.HasValidColumn(c); err != nil {
+			return err
+		}
+	}
+	if fromCol > toCol {
+		return fmt.Errorf("Invalid header group, fromCol: %d > toCol: %d", fromCol, toCol)
+	}
+
+	g := HeaderGroup{Title: title, FromCol: fromCol, ToCol: toCol}
+	for i := 0; i < len(t.HeaderGroups); i++ {
+		o := t.HeaderGroups[i]
+		if o.FromCol == g.FromCol && o.ToCol == g.ToCol {
+			return fmt.Errorf("Header group already exists for columns %d-%d", fromCol, toCol)
+		}
+		if g.overlaps(o) && !g.contains(o) && !o.contains(g) {
+			return fmt.Errorf("Header group overlaps header group %q without nesting", o.Title)
+		}
+	}
+	t.HeaderGroups = append(t.HeaderGroups, g)
+	return nil
+}
+
+// getHeaderGroupRows returns the rows of header groups, top row first. Each
+// row covers all the columns, columns without a group in a row are covered by
+// untitled single column groups.
+func (t *Table) getHeaderGroupRows() [][]HeaderGroup {
+	if len(t.HeaderGroups) == 0 {
+		return nil
+	}
+
+	// level of a group is one more than the level of the groups it contains,
+	// groups which contain no other group are right above the column headers
+	levels := make([]int, len(t.HeaderGroups))
+	var level func(i int) int
+	level = func(i int) int {
+		if levels[i] > 0 {
+			return levels[i]
+		}
+		l := 1
+		for j := 0; j < len(t.HeaderGroups); j++ {
+			if j != i && t.HeaderGroups[i].contains(t.HeaderGroups[j]) {
+				if k := level(j) + 1; k > l {
+					l = k
+				}
+			}
+		}
+		levels[i] = l
+		return l
+	}
+	maxLevel := 0
+	for i := 0; i < len(t.HeaderGroups); i++ {
+		if l := level(i); l > maxLevel {
+			maxLevel = l
+		}
+	}
+
+	rows := make([][]HeaderGroup, maxLevel)
+	for l := maxLevel; l > 0; l-- {
+		var row []HeaderGroup
+		for i := 0; i < len(t.HeaderGroups); i++ {
+			if levels[i] == l {
+				row = append(row, t.HeaderGroups[i])
+			}
+		}
+		sort.Slice(row, func(a, b int) bool { return row[a].FromCol < row[b].FromCol })
+
+		// fill the gaps with untitled groups
+		var filled []HeaderGroup
+		col := 0
+		for _, g := range row {
+			for ; col < g.FromCol; col++ {
+				filled = append(filled, HeaderGroup{FromCol: col, ToCol: col})
+			}
+			filled = append(filled, g)
+			col = g.ToCol + 1
+		}
+		for ; col < t.ColCount(); col++ {
+			filled = append(filled, HeaderGroup{FromCol: col, ToCol: col})
+		}
+		rows[maxLevel-l] = filled
+	}
+	return rows
+}
@@ -0,0 +1,149 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHeaderGroup(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Header Group Table")
+	tbl.AddColumn("Name", 6, CELLSTRING, COLJUSTIFYLEFT)
+	for i := 0; i < 2; i++ {
+		tbl.AddColumn("Revenue", 7, CELLINT, COLJUSTIFYRIGHT)
+		tbl.AddColumn("Cost", 7, CELLINT, COLJUSTIFYRIGHT)
+	}
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "north")
+	for i := 1; i < 5; i++ {
+		tbl.Puti(-1, i, int64(i*100))
+	}
+
+	// outer group is added first, nesting decides the levels
+	var groups = []HeaderGroup{{"2017", 1, 4}, {"Q1", 1, 2}, {"Q2", 3, 4}}
+	for _, g := range groups {
+		if err := tbl.AddHeaderGroup(g.Title, g.FromCol, g.ToCol); err != nil {
+			t.Fatalf("headergroup_test: Error adding header group: %s\n", err.Error())
+		}
+	}
+	var invalid = []HeaderGroup{{"same", 1, 2}, {"crossing", 2, 3}, {"reversed", 2, 1}, {"beyond", 4, 5}}
+	for _, g := range invalid {
+		if err := tbl.AddHeaderGroup(g.Title, g.FromCol, g.ToCol); err == nil {
+			t.Errorf("headergroup_test: Expected error for header group %v\n", g)
+		}
+	}
+
+	// text: stacked centered titles above column headers
+	var temp bytes.Buffer
+	if err := tbl.TextprintTable(&temp); err != nil {
+		t.Errorf("headergroup_test: Error creating TEXT output: %s\n", err.Error())
+	}
+	expected := "Header Group Table\n" +
+		"                       2017               \n" +
+		"        ----------------------------------\n" +
+		"               Q1                Q2       \n" +
+		"        ----------------  ----------------\n" +
+		"Name    Revenue     Cost  Revenue     Cost\n"
+	if !strings.HasPrefix(temp.String(), expected) {
+		t.Errorf("headergroup_test: Expected text output to start with:\n%s\nbut found:\n%s\n", expected, temp.String())
+	}
+
+	// html: header rows with colspans
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("headergroup_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := strings.Join(strings.Fields(temp.String()), " ")
+	for _, e := range []string{
+		`<thead> <tr class="header-group"> <th></th> <th class="header-group-title" colspan="4"> 2017 </th> </tr>`,
+		`<tr class="header-group"> <th></th> <th class="header-group-title" colspan="2"> Q1 </th> <th class="header-group-title" colspan="2"> Q2 </th> </tr> <tr> <th class="header-0">`,
+		`table thead tr.header-group th.header-group-title{text-align:center;`,
+	} {
+		if !strings.Contains(s, e) {
+			t.Errorf("headergroup_test: Expected %q in html output, but found:\n%s\n", e, temp.String())
+		}
+	}
+
+	// csv: group titles in their first column
+	temp.Reset()
+	if err := tbl.CSVprintTable(&temp); err != nil {
+		t.Errorf("headergroup_test: Error creating CSV output: %s\n", err.Error())
+	}
+	e := "\"\",\"2017\",\"\",\"\",\"\"\n\"\",\"Q1\",\"\",\"Q2\",\"\"\n\"Name\","
+	if !strings.Contains(temp.String(), e) {
+		t.Errorf("headergroup_test: Expected %q in csv output, but found:\n%s\n", e, temp.String())
+	}
+
+	// xlsx: merged header cells
+	temp.Reset()
+	if err := tbl.XLSXprintTable(&temp); err != nil {
+		t.Fatalf("headergroup_test: Error creating XLSX output: %s\n", err.Error())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(temp.Bytes()), int64(temp.Len()))
+	if err != nil {
+		t.Fatalf("headergroup_test: Error reading XLSX output: %s\n", err.Error())
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			b, _ := ioutil.ReadAll(r)
+			sheet = string(b)
+		}
+	}
+	for _, e := range []string{`<mergeCell ref="B2:E2"/>`, `<mergeCell ref="B3:C3"/>`, `<mergeCell ref="D3:E3"/>`, `ySplit="4"`} {
+		if !strings.Contains(sheet, e) {
+			t.Errorf("headergroup_test: Expected %q in xlsx sheet, but found:\n%s\n", e, sheet)
+		}
+	}
+
+	// pdf: only checks that header groups are rendered
+	temp.Reset()
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Errorf("headergroup_test: Error creating PDF output: %s\n", err.Error())
+	}
+
+	// header groups survive json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("headergroup_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("headergroup_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if len(tbl2.HeaderGroups) != 3 || tbl2.HeaderGroups[0] != groups[0] {
+		t.Errorf("headergroup_test: Expected header groups to be preserved, found %v\n", tbl2.HeaderGroups)
+	}
+}
+
+func TestHeaderGroupMultibyte(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Width", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Height", 6, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Revenue", 7, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddHeaderGroup("Größe", 1, 2)
+	tbl.AddHeaderGroup("€€€€€€€€€€", 3, 3)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "box")
+
+	// titles are centered and cut by characters, not bytes
+	var temp bytes.Buffer
+	if err := tbl.TextprintTable(&temp); err != nil {
+		t.Errorf("headergroup_test: Error creating TEXT output: %s\n", err.Error())
+	}
+	expected := "            Größe      €€€€€€€\n" +
+		"        -------------  -------\n" +
+		"Name    Width  Height  Revenue\n"
+	if !strings.HasPrefix(temp.String(), expected) || !utf8.ValidString(temp.String()) {
+		t.Errorf("headergroup_test: Expected text output to start with:\n%s\nbut found:\n%s\n", expected, temp.String())
+	}
+}
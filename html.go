@@ -4,14 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
-	"path"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"text/template"
 
-	"github.com/dustin/go-humanize"
-	"github.com/kardianos/osext"
 	"github.com/yosssi/gohtml"
 )
 
@@ -26,6 +26,10 @@ const (
 
 	NOROWSCLASS    = `no-rows`
 	NOHEADERSCLASS = `no-headers`
+	ALLCELLSCLASS  = `all-cells` // css map key and row class of all cells
+
+	HEADERGROUPCLASS      = `header-group`       // class of the header rows holding header groups
+	HEADERGROUPTITLECLASS = `header-group-title` // class of a header cell holding group title
 
 	// HEADERSCLASS        = `headers`
 	// DATACLASS           = `data`
@@ -37,6 +41,19 @@ type HTMLTable struct {
 	StyleString string
 	outbuf      bytes.Buffer
 	fontUnit    string
+	fragment    bool            // output table container only, without page template
+	containerID string          // id of table container, scopes the generated css
+	cellRules   []htmlCSSRule   // css rules of the body cells, written by getRows
+	cellClasses map[string]bool // classes whose rules are in cellRules
+}
+
+// htmlCSSRule is a css rule of the body cells, rules are written in the order
+// in which their properties were set, see Table.setCSS
+type htmlCSSRule struct {
+	seq      int
+	level    int // 0 all cells, 1 row, 2 column, 3 cell, breaks ties of seq
+	selector string
+	props    []*CSSProperty
 }
 
 // HTMLTemplateContext holds the context for table html template
@@ -51,6 +68,26 @@ func (ht *HTMLTable) SetCSSFontUnit(fontUnit string) {
 }
 
 func (ht *HTMLTable) writeTableOutput(w io.Writer) error {
+	tContainer := ht.getContainer()
+
+	if ht.fragment {
+		// no page template, stylesheet goes right before the container
+		css, err := ht.getFragmentCSS()
+		if err != nil {
+			return err
+		}
+		ht.outbuf.WriteString(gohtml.Format(`<style>` + css + `</style>` + tContainer))
+	} else if err := ht.formatHTML(tContainer); err != nil {
+		return err
+	}
+
+	// write output to passed io.Writer interface object
+	_, err := w.Write(ht.outbuf.Bytes())
+	return err
+}
+
+// getContainer returns the table container div with title, sections and table
+func (ht *HTMLTable) getContainer() string {
 	var tContainer string
 
 	// if font unit not set then set default one
@@ -77,7 +114,7 @@ func (ht *HTMLTable) writeTableOutput(w io.Writer) error {
 	if headerStr, err := ht.getHeaders(); err != nil {
 		if cellCSSProps, ok := ht.getCSSPropertyList(NOHEADERSCLASS); ok {
 			// get css string for section1
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` p`
+			ht.StyleString += ht.getContainerSelector() + ` p`
 			ht.StyleString += ht.getCSSForClassSelector(NOHEADERSCLASS, cellCSSProps)
 		}
 		tableOut += `<p class="` + NOHEADERSCLASS + `">` + err.Error() + `</p>`
@@ -87,11 +124,16 @@ func (ht *HTMLTable) writeTableOutput(w io.Writer) error {
 			colSpan := strconv.Itoa(ht.Table.ColCount())
 			if cellCSSProps, ok := ht.getCSSPropertyList(NOROWSCLASS); ok {
 				// get css string for section1
-				ht.StyleString += `div.` + TABLECONTAINERCLASS + ` table tbody tr td`
+				ht.StyleString += ht.getContainerSelector() + ` table tbody tr td`
 				ht.StyleString += ht.getCSSForClassSelector(NOROWSCLASS, cellCSSProps)
 			}
 			noRowsTD := `<td colspan="` + colSpan + `" class="` + NOROWSCLASS + `">` + err.Error() + `</td>`
-			tableOut += `<tbody><tr>` + noRowsTD + `</tr></tbody>`
+			// footer, e.g. a count of 0, is shown under the headers without rows
+			if len(ht.Table.Footer) > 0 {
+				tableOut += headerStr + `<tbody><tr>` + noRowsTD + `</tr></tbody>` + ht.getFooter()
+			} else {
+				tableOut += `<tbody><tr>` + noRowsTD + `</tr></tbody>`
+			}
 		} else {
 			// if rows exist, then only show headers
 			tableOut += headerStr
@@ -110,16 +152,138 @@ func (ht *HTMLTable) writeTableOutput(w io.Writer) error {
 		tContainer += tableOut
 	}
 
+	// script goes inside the container, it finds the table from there
+	if ht.isInteractive() && tableOut != "" {
+		ht.StyleString += ht.getInteractiveCSS()
+		tContainer += ht.getInteractiveScript()
+	}
+
 	// wrap it up in a div with a class
-	tContainer = `<div class="` + TABLECONTAINERCLASS + `">` + tContainer + `</div>`
+	var idAttr string
+	if ht.containerID != "" {
+		idAttr = ` id="` + template.HTMLEscapeString(ht.containerID) + `"`
+	}
+	return `<div class="` + TABLECONTAINERCLASS + `"` + idAttr + `>` + tContainer + `</div>`
+}
 
-	if err := ht.formatHTML(tContainer); err != nil {
-		return err
+// getFragment returns the formatted table container and its stylesheet
+func (ht *HTMLTable) getFragment() (string, string, error) {
+	// container must be built first, it collects the css of cells
+	tContainer := gohtml.Format(ht.getContainer())
+	css, err := ht.getFragmentCSS()
+	if err != nil {
+		return "", "", err
 	}
+	return tContainer, css, nil
+}
 
-	// write output to passed io.Writer interface object
-	_, err := w.Write(ht.outbuf.Bytes())
-	return err
+// getThemeCSS returns the css of table theme, it overrides the default css
+func (ht *HTMLTable) getThemeCSS() string {
+	if ht.Table.theme == nil {
+		return ""
+	}
+	return ht.Table.theme.getCSS(ht.getContainerSelector())
+}
+
+// getContainerSelector returns the css selector of table container, scoped
+// by container id if there is one
+func (ht *HTMLTable) getContainerSelector() string {
+	if ht.containerID != "" {
+		return `div#` + ht.containerID + `.` + TABLECONTAINERCLASS
+	}
+	return `div.` + TABLECONTAINERCLASS
+}
+
+// getFragmentCSS returns the stylesheet of html fragment. Rules of the default
+// or custom css are scoped by the container id, rules for the page (html, body)
+// are dropped so that they don't leak into the host page
+func (ht *HTMLTable) getFragmentCSS() (string, error) {
+	css, err := ht.getTableCSS()
+	if err != nil {
+		return "", err
+	}
+	scoped, err := scopeCSS(cssComment.ReplaceAllString(css, ""), ht.getContainerSelector())
+	if err != nil {
+		return "", err
+	}
+	return scoped + ht.getThemeCSS() + ht.StyleString, nil
+}
+
+// cssComment matches a css comment
+var cssComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// scopeCSS returns the rules of css with their selectors scoped by container,
+// those of @media and @supports blocks too. Selectors of the table container
+// get the container selector instead of div.rpt-table-container, others are
+// prefixed by it. Other at-rules, e.g. @font-face, are kept as they are.
+func scopeCSS(css, container string) (string, error) {
+	var scoped string
+	for css = strings.TrimSpace(css); css != ""; css = strings.TrimSpace(css) {
+		i := strings.IndexAny(css, "{};")
+		if i < 0 || css[i] == '}' {
+			return "", fmt.Errorf("Invalid css near: %.40s", css)
+		}
+		if css[i] == ';' { // at-rule without block, e.g. @import
+			scoped += css[:i+1]
+			css = css[i+1:]
+			continue
+		}
+
+		// block up to the matching brace
+		depth, j := 0, i
+		for ; j < len(css); j++ {
+			if css[j] == '{' {
+				depth++
+			} else if css[j] == '}' {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		if j == len(css) {
+			return "", fmt.Errorf("Unbalanced braces in css near: %.40s", css)
+		}
+		prelude, block := strings.TrimSpace(css[:i]), css[i+1:j]
+		css = css[j+1:]
+
+		switch {
+		case strings.HasPrefix(prelude, "@media") || strings.HasPrefix(prelude, "@supports"):
+			inner, err := scopeCSS(block, container)
+			if err != nil {
+				return "", err
+			}
+			scoped += prelude + `{` + inner + `}`
+		case strings.HasPrefix(prelude, "@"):
+			scoped += prelude + `{` + block + `}`
+		default:
+			var selectors []string
+			for _, sel := range strings.Split(prelude, ",") {
+				sel = strings.TrimSpace(sel)
+				switch {
+				case strings.HasPrefix(sel, `div.`+TABLECONTAINERCLASS):
+					selectors = append(selectors, container+strings.TrimPrefix(sel, `div.`+TABLECONTAINERCLASS))
+				case isPageSelector(sel):
+				default:
+					selectors = append(selectors, container+` `+sel)
+				}
+			}
+			if len(selectors) > 0 {
+				scoped += strings.Join(selectors, ",") + `{` + block + `}`
+			}
+		}
+	}
+	return scoped, nil
+}
+
+// isPageSelector reports whether a css selector styles the page rather than
+// what is in it, i.e. it starts with html, body or :root
+func isPageSelector(sel string) bool {
+	for _, p := range []string{"html", "body", ":root"} {
+		if strings.HasPrefix(sel, p) && (len(sel) == len(p) || strings.ContainsRune(" .#:[>+~", rune(sel[len(p)]))) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ht *HTMLTable) formatHTML(htmlString string) error {
@@ -127,13 +291,13 @@ func (ht *HTMLTable) formatHTML(htmlString string) error {
 
 	// make context for template
 	htmlContext := HTMLTemplateContext{FontSize: CSSFONTSIZE}
-	htmlContext.HeadTitle = ht.Table.Title
+	htmlContext.HeadTitle = template.HTMLEscapeString(ht.Table.Title)
 
 	htmlContext.DefaultCSS, err = ht.getTableCSS()
 	if err != nil {
 		return err
 	}
-	htmlContext.DefaultCSS = `<style>` + htmlContext.DefaultCSS + `</style>`
+	htmlContext.DefaultCSS = `<style>` + htmlContext.DefaultCSS + ht.getThemeCSS() + `</style>`
 	htmlContext.CustomCSS = `<style>` + ht.StyleString + `</style>`
 	htmlContext.TableHTML = htmlString
 
@@ -163,10 +327,10 @@ func (ht *HTMLTable) getTitle() string {
 	if title != "" {
 		if cellCSSProps, ok := ht.getCSSPropertyList(TITLECLASS); ok {
 			// get css string for title
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` p`
+			ht.StyleString += ht.getContainerSelector() + ` p`
 			ht.StyleString += ht.getCSSForClassSelector(TITLECLASS, cellCSSProps)
 		}
-		return `<p class="` + TITLECLASS + `">` + title + `</p>`
+		return `<p class="` + TITLECLASS + `">` + template.HTMLEscapeString(title) + `</p>`
 	}
 
 	// blank return
@@ -179,10 +343,10 @@ func (ht *HTMLTable) getSection1() string {
 	if section1 != "" {
 		if cellCSSProps, ok := ht.getCSSPropertyList(SECTION1CLASS); ok {
 			// get css string for section1
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` p`
+			ht.StyleString += ht.getContainerSelector() + ` p`
 			ht.StyleString += ht.getCSSForClassSelector(SECTION1CLASS, cellCSSProps)
 		}
-		return `<p class="` + SECTION1CLASS + `">` + section1 + `</p>`
+		return `<p class="` + SECTION1CLASS + `">` + template.HTMLEscapeString(section1) + `</p>`
 	}
 
 	// blank return
@@ -195,10 +359,10 @@ func (ht *HTMLTable) getSection2() string {
 	if section2 != "" {
 		if cellCSSProps, ok := ht.getCSSPropertyList(SECTION2CLASS); ok {
 			// get css string for section2
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` p`
+			ht.StyleString += ht.getContainerSelector() + ` p`
 			ht.StyleString += ht.getCSSForClassSelector(SECTION2CLASS, cellCSSProps)
 		}
-		return `<p class="` + SECTION2CLASS + `">` + section2 + `</p>`
+		return `<p class="` + SECTION2CLASS + `">` + template.HTMLEscapeString(section2) + `</p>`
 	}
 
 	// blank return
@@ -211,10 +375,10 @@ func (ht *HTMLTable) getSection3() string {
 	if section3 != "" {
 		if cellCSSProps, ok := ht.getCSSPropertyList(SECTION3CLASS); ok {
 			// get css string for section3
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` p`
+			ht.StyleString += ht.getContainerSelector() + ` p`
 			ht.StyleString += ht.getCSSForClassSelector(SECTION3CLASS, cellCSSProps)
 		}
-		return `<p class="` + SECTION3CLASS + `">` + section3 + `</p>`
+		return `<p class="` + SECTION3CLASS + `">` + template.HTMLEscapeString(section3) + `</p>`
 	}
 
 	// blank return
@@ -307,14 +471,41 @@ func (ht *HTMLTable) getHeaders() (string, error) {
 		cellCSSProps, _ := ht.getCSSPropertyList(thClass)
 
 		// get css string for headers
-		ht.StyleString += `div.` + TABLECONTAINERCLASS + ` table thead tr th`
-		// ht.StyleString += `div.` + TABLECONTAINERCLASS + ` table thead.` + HEADERSCLASS + ` tr th`
+		ht.StyleString += ht.getContainerSelector() + ` table thead tr th`
+		// ht.StyleString += ht.getContainerSelector() + ` table thead.` + HEADERSCLASS + ` tr th`
 		ht.StyleString += ht.getCSSForClassSelector(thClass, cellCSSProps)
 
-		tHeaders += `<th class="` + thClass + `">` + headerCell.ColTitle + `</th>`
+		tHeaders += `<th class="` + thClass + `"` + ht.getInteractiveType(headerIndex) + `>` + template.HTMLEscapeString(headerCell.ColTitle) + `</th>`
 	}
 
-	return `<thead><tr>` + tHeaders + `</tr></thead>`, nil
+	// header groups are stacked above the column headers
+	var tGroups string
+	for _, groups := range ht.Table.getHeaderGroupRows() {
+		tGroups += ht.getHeaderGroupRow(groups)
+	}
+	if tGroups != "" {
+		c := ht.getContainerSelector()
+		ht.StyleString += c + ` table thead tr.` + HEADERGROUPCLASS + ` th{border-bottom:none;padding-top:5px}`
+		ht.StyleString += c + ` table thead tr.` + HEADERGROUPCLASS + ` th.` + HEADERGROUPTITLECLASS + `{text-align:center;border-bottom:1px solid #BBB}`
+	}
+
+	return `<thead>` + tGroups + `<tr>` + tHeaders + `</tr></thead>`, nil
+}
+
+// getHeaderGroupRow returns a header row of header groups
+func (ht *HTMLTable) getHeaderGroupRow(groups []HeaderGroup) string {
+	var tr string
+	for _, g := range groups {
+		var attrs string
+		if g.Title != "" {
+			attrs = ` class="` + HEADERGROUPTITLECLASS + `"`
+		}
+		if g.ToCol > g.FromCol {
+			attrs += ` colspan="` + strconv.Itoa(g.ToCol-g.FromCol+1) + `"`
+		}
+		tr += `<th` + attrs + `>` + template.HTMLEscapeString(g.Title) + `</th>`
+	}
+	return `<tr class="` + HEADERGROUPCLASS + `">` + tr + `</tr>`
 	// return `<thead class="` + HEADERSCLASS + `"><tr>` + tHeaders + `</tr></thead>`, nil
 }
 
@@ -328,12 +519,110 @@ func (ht *HTMLTable) getRows() (string, error) {
 
 	var rowsStr string
 	for i := 0; i < ht.Table.RowCount(); i++ {
+		// interactive output keeps each group of rows in its own tbody
+		if ht.isInteractive() && ht.isGroupStart(i) && !ht.Table.isRowSpanned(i) {
+			rowsStr += `</tbody><tbody>`
+		}
 		// for valid row, we will never get an error
 		s, _ := ht.getRow(i)
 		rowsStr += s
 	}
 
-	return `<tbody>` + rowsStr + `</tbody>`, nil
+	// css of all cells, rows, columns and cells collected by getRow. The rules
+	// are equally specific, the one written last wins, just like the call of
+	// setCSS made last.
+	sort.SliceStable(ht.cellRules, func(i, j int) bool {
+		a, b := ht.cellRules[i], ht.cellRules[j]
+		if a.seq != b.seq {
+			return a.seq < b.seq
+		}
+		return a.level < b.level
+	})
+	for _, r := range ht.cellRules {
+		ht.StyleString += ht.getCSSForSelector(r.selector, r.props)
+	}
+	ht.cellRules, ht.cellClasses = nil, nil
+
+	return `<tbody>` + rowsStr + `</tbody>` + ht.getFooter(), nil
+}
+
+// getFooter returns the footer rows in a tfoot, which is repeated on each page
+// when printed
+func (ht *HTMLTable) getFooter() string {
+	if len(ht.Table.Footer) == 0 {
+		return ""
+	}
+	c := ht.getContainerSelector()
+	ht.StyleString += c + ` table tfoot{display:table-footer-group}`
+	ht.StyleString += c + ` table tfoot tr td{font-weight:bold}`
+	ht.StyleString += c + ` table tfoot tr:first-child td{border-top:1px solid #BBB}`
+
+	// column rules of the body don't apply to tfoot
+	for colIndex := 0; colIndex < ht.Table.ColCount(); colIndex++ {
+		tdClass := ht.Table.getCSSMapKeyForCol(colIndex)
+		if colCSSProps, ok := ht.getCSSPropertyList(tdClass); ok {
+			ht.StyleString += c + ` table tfoot tr td`
+			ht.StyleString += ht.getCSSForClassSelector(tdClass, colCSSProps)
+		}
+	}
+
+	var tFoot string
+	for i := 0; i < len(ht.Table.Footer); i++ {
+		var tr string
+		cs := ht.Table.getFooterRow(i)
+		for colIndex := 0; colIndex < len(cs.Col); colIndex++ {
+			var tdAttrs string
+			if _, ok := ht.Table.CSS[ht.Table.getCSSMapKeyForCol(colIndex)]; ok {
+				tdAttrs = ` class="` + ht.Table.getCSSMapKeyForCol(colIndex) + `"`
+			}
+			tr += `<td` + tdAttrs + `>` + ht.getCellHTML(cs.Col[colIndex], colIndex) + `</td>`
+		}
+		tFoot += `<tr>` + tr + `</tr>`
+	}
+	return `<tfoot>` + tFoot + `</tfoot>`
+}
+
+// getCellHTML returns the formatted content of a cell in column colIndex
+func (ht *HTMLTable) getCellHTML(c Cell, colIndex int) string {
+	var rowCell string
+	// append content in TD
+	switch c.Type {
+	case CELLFLOAT:
+		rowCell = ht.Table.ColDefs[colIndex].floatText(c.Fval)
+	case CELLINT:
+		rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, c.Ival)
+	case CELLSTRING:
+		// ******************************************************
+		// FOR HTML, APPEND FULL STRING, THERE ARE NO
+		// MULTILINE TEXT IN THIS
+		// ******************************************************
+		// escape the content unless it is trusted html set by PutHTML
+		rowCell = c.Sval
+		if !c.HTML {
+			rowCell = template.HTMLEscapeString(rowCell)
+		}
+	case CELLDATE:
+		rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, c.Dval.Format(ht.Table.DateFmt))
+	case CELLDATETIME:
+		rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, c.Dval.Format(ht.Table.DateTimeFmt))
+	default:
+		rowCell = mkstr(ht.Table.ColDefs[colIndex].Width, ' ')
+	}
+	return rowCell
+}
+
+// getMergeAttrs returns the colspan and rowspan attributes of a merged cell
+func (ht *HTMLTable) getMergeAttrs(rowIndex, colIndex int) string {
+	var attrs string
+	if m := ht.Table.getMerge(rowIndex, colIndex); m != nil {
+		if m.ColSpan > 1 {
+			attrs += ` colspan="` + strconv.Itoa(m.ColSpan) + `"`
+		}
+		if m.RowSpan > 1 {
+			attrs += ` rowspan="` + strconv.Itoa(m.RowSpan) + `"`
+		}
+	}
+	return attrs
 }
 
 func (ht *HTMLTable) getRow(rowIndex int) (string, error) {
@@ -350,7 +639,19 @@ func (ht *HTMLTable) getRow(rowIndex int) (string, error) {
 
 	// format table rows
 	var tRow string
-	var trClass string
+	var trClasses []string
+
+	// classes of all cells and of the row, rules are written by getRows
+	cs := ht.Table.Row[rowIndex]
+	trSelector := func(class string) string { return ht.getContainerSelector() + ` table tbody tr.` + class + ` td` }
+	if class, ok := ht.getCellClass(ALLCELLSCLASS, ALLCELLSCLASS, 0, cs, trSelector); ok {
+		trClasses = append(trClasses, class)
+	}
+	rowKey := ht.Table.getCSSMapKeyForRow(rowIndex)
+	if class, ok := ht.getCellClass(rowKey, rowKey, 1, cs, trSelector); ok {
+		trClasses = append(trClasses, class)
+	}
+	tdSelector := func(class string) string { return ht.getContainerSelector() + ` table tbody tr td.` + class }
 
 	if len(ht.Table.LineBefore) > 0 {
 		j := sort.SearchInts(ht.Table.LineBefore, rowIndex)
@@ -358,48 +659,38 @@ func (ht *HTMLTable) getRow(rowIndex int) (string, error) {
 		// If YES, then discard it
 		sepExist := sort.SearchInts(ht.Table.LineAfter, rowIndex-1) < ht.Table.RowCount()
 		if j < len(ht.Table.LineBefore) && rowIndex == ht.Table.LineBefore[j] && !sepExist {
-			trClass += `top-line`
+			trClasses = append(trClasses, `top-line`)
 		}
 	}
 
 	// fill the content in rowTextList for the first line
 	for colIndex := 0; colIndex < len(ht.Table.Row[rowIndex].Col); colIndex++ {
 
-		var rowCell string
-		// append content in TD
-		switch ht.Table.Row[rowIndex].Col[colIndex].Type {
-		case CELLFLOAT:
-			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, humanize.FormatFloat("#,###.##", ht.Table.Row[rowIndex].Col[colIndex].Fval))
-		case CELLINT:
-			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, ht.Table.Row[rowIndex].Col[colIndex].Ival)
-		case CELLSTRING:
-			// ******************************************************
-			// FOR HTML, APPEND FULL STRING, THERE ARE NO
-			// MULTILINE TEXT IN THIS
-			// ******************************************************
-			rowCell = fmt.Sprintf("%s", ht.Table.Row[rowIndex].Col[colIndex].Sval)
-		case CELLDATE:
-			rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, ht.Table.Row[rowIndex].Col[colIndex].Dval.Format(ht.Table.DateFmt))
-		case CELLDATETIME:
-			rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, ht.Table.Row[rowIndex].Col[colIndex].Dval.Format(ht.Table.DateTimeFmt))
-		default:
-			rowCell = mkstr(ht.Table.ColDefs[colIndex].Width, ' ')
+		// cells hidden by a merge are not rendered, the merged cell spans them
+		if ht.Table.isMergeCovered(rowIndex, colIndex) {
+			continue
 		}
 
-		// format td cell with custom class if exists for it
-		g := ht.Table.getCSSMapKeyForCell(rowIndex, colIndex)
-		if cellCSSProps, ok := ht.getCSSPropertyList(g); ok {
-
-			tdClass := `cell-row-` + strconv.Itoa(rowIndex) + `-col-` + strconv.Itoa(colIndex)
+		rowCell := ht.getCellHTML(ht.Table.Row[rowIndex].Col[colIndex], colIndex)
 
-			// get css string for a row
-			ht.StyleString += `div.` + TABLECONTAINERCLASS + ` table tbody tr td`
-			ht.StyleString += ht.getCSSForClassSelector(tdClass, cellCSSProps)
+		// column class, and a class of the cell for genuine per cell
+		// overrides, rules are written by getRows
+		var tdClasses []string
+		colKey := ht.Table.getCSSMapKeyForCol(colIndex)
+		if class, ok := ht.getCellClass(colKey, colKey, 2, cs, tdSelector); ok {
+			tdClasses = append(tdClasses, class)
+		}
+		cellClass := `cell-row-` + strconv.Itoa(rowIndex) + `-col-` + strconv.Itoa(colIndex)
+		if class, ok := ht.getCellClass(ht.Table.getCSSMapKeyForCell(rowIndex, colIndex), cellClass, 3, cs, tdSelector); ok {
+			tdClasses = append(tdClasses, class)
+		}
 
-			rowCell = `<td class="` + tdClass + `">` + rowCell + `</td>`
-		} else {
-			rowCell = `<td>` + rowCell + `</td>`
+		tdAttrs := ht.getInteractiveValue(ht.Table.Row[rowIndex].Col[colIndex], colIndex)
+		if len(tdClasses) > 0 {
+			tdAttrs = ` class="` + strings.Join(tdClasses, ` `) + `"` + tdAttrs
 		}
+		tdAttrs += ht.getMergeAttrs(rowIndex, colIndex)
+		rowCell = `<td` + tdAttrs + `>` + rowCell + `</td>`
 
 		tRow += rowCell
 	}
@@ -407,23 +698,77 @@ func (ht *HTMLTable) getRow(rowIndex int) (string, error) {
 	if len(ht.Table.LineAfter) > 0 {
 		j := sort.SearchInts(ht.Table.LineAfter, rowIndex)
 		if j < len(ht.Table.LineAfter) && rowIndex == ht.Table.LineAfter[j] {
-			trClass += `bottom-line`
+			trClasses = append(trClasses, `bottom-line`)
 		}
 	}
 
-	if trClass != "" {
-		return `<tr class="` + trClass + `">` + tRow + `</tr>`, nil
+	if len(trClasses) > 0 {
+		return `<tr class="` + strings.Join(trClasses, ` `) + `">` + tRow + `</tr>`, nil
 	}
 	return `<tr>` + tRow + `</tr>`, nil
 
 }
 
+// getCellClass returns the class which applies the css stored under key to the
+// cells of row cs, ok is false if there is none. Properties set before the row
+// was added don't apply to it. The class is class if all properties apply,
+// otherwise class followed by "-part-" and the number of the ones which don't.
+// The rules of the class are added to cellRules, one for the properties of
+// each call of setCSS, selector returns the selector of the class.
+func (ht *HTMLTable) getCellClass(key, class string, level int, cs Colset, selector func(class string) string) (string, bool) {
+	cssProps, ok := ht.getCSSPropertyList(key)
+	if !ok {
+		return "", false
+	}
+	var props []*CSSProperty
+	for _, p := range cssProps {
+		// properties without sequence number are not stored by setCSS, they
+		// apply to all rows
+		if p.seq == 0 || p.seq > cs.cssSeq {
+			props = append(props, p)
+		}
+	}
+	if len(props) == 0 {
+		return "", false
+	}
+	if len(props) < len(cssProps) {
+		class += `-part-` + strconv.Itoa(len(cssProps)-len(props))
+	}
+
+	if !ht.cellClasses[class] {
+		if ht.cellClasses == nil {
+			ht.cellClasses = make(map[string]bool)
+		}
+		ht.cellClasses[class] = true
+		bySeq := map[int]*htmlCSSRule{}
+		var seqs []int
+		for _, p := range props {
+			r, ok := bySeq[p.seq]
+			if !ok {
+				r = &htmlCSSRule{seq: p.seq, level: level, selector: selector(class)}
+				bySeq[p.seq] = r
+				seqs = append(seqs, p.seq)
+			}
+			r.props = append(r.props, p)
+		}
+		for _, seq := range seqs {
+			ht.cellRules = append(ht.cellRules, *bySeq[seq])
+		}
+	}
+	return class, true
+}
+
 // getCSSForClassSelector returns css string for a class
 func (ht *HTMLTable) getCSSForClassSelector(className string, cssList []*CSSProperty) string {
+	return ht.getCSSForSelector(`.`+className, cssList)
+}
+
+// getCSSForSelector returns css string for any selector
+func (ht *HTMLTable) getCSSForSelector(selector string, cssList []*CSSProperty) string {
 	var classCSS string
 
 	// append notation for selector
-	classCSS += `.` + className + `{`
+	classCSS += selector + `{`
 
 	for _, cssProp := range cssList {
 		// append css property name
@@ -458,23 +803,17 @@ func (ht *HTMLTable) getCSSForClassSelector(className string, cssList []*CSSProp
 
 // getTableCSS reads default css and return the content of it
 func (ht *HTMLTable) getTableCSS() (string, error) {
-	// 1. Get the content from custom css file if it exist
-	cssPath := ht.Table.htmlTemplateCSS
-	if ok, _ := isValidFilePath(cssPath); ok {
-		cssString, err := ioutil.ReadFile(cssPath)
+	// 1. Get the content from css of template file system if it is set
+	if ht.Table.htmlTemplateFS != nil && ht.Table.htmlTemplateFSCSS != "" {
+		cssString, err := fs.ReadFile(ht.Table.htmlTemplateFS, ht.Table.htmlTemplateFSCSS)
 		if err != nil {
 			return "", err
 		}
 		return string(cssString), nil
 	}
 
-	// 2. Get the content from default file, from within the execution path
-	// in case first trial failed
-	exDirPath, err := osext.ExecutableFolder()
-	if err != nil {
-		return "", err
-	}
-	cssPath = path.Join(exDirPath, "gotable.css")
+	// 2. Get the content from custom css file if it exist
+	cssPath := ht.Table.htmlTemplateCSS
 	if ok, _ := isValidFilePath(cssPath); ok {
 		cssString, err := ioutil.ReadFile(cssPath)
 		if err != nil {
@@ -483,53 +822,36 @@ func (ht *HTMLTable) getTableCSS() (string, error) {
 		return string(cssString), nil
 	}
 
-	// 3. Get content from constant value defined in defaults.go
-	// in case second trial failed
+	// 3. Get the embedded default css in case other trials failed
 	return DCSS, nil
 }
 
 // getHTMLTemplate returns the *Template object, error
 func (ht *HTMLTable) getHTMLTemplate() (*template.Template, error) {
 
-	// 1. Get the content from custom template file if it exist
-	tmplPath := ht.Table.htmlTemplate
-	if ok, _ := isValidFilePath(tmplPath); ok {
-
-		// generates new template and parse content from html and returns it
-		if tmpl, err := template.ParseFiles(tmplPath); err != nil {
-			goto tmplexdir2
-		} else {
-			// if no error then return simply
-			return tmpl, err
-		}
+	// 1. Use the template parsed by the caller as it is
+	if ht.Table.htmlTemplateParsed != nil {
+		return ht.Table.htmlTemplateParsed, nil
 	}
 
-	// 2. Get the content from default file, from within the execution path
-	// in case first trial failed
-tmplexdir2:
-	exDirPath, err := osext.ExecutableFolder()
-	if err != nil {
-		return nil, err
+	// 2. Get the content from template file system if it is set
+	if ht.Table.htmlTemplateFS != nil && ht.Table.htmlTemplateFSName != "" {
+		return template.ParseFS(ht.Table.htmlTemplateFS, ht.Table.htmlTemplateFSName)
 	}
 
-	tmplPath = path.Join(exDirPath, "gotable.tmpl")
+	// 3. Get the content from custom template file if it exist
+	tmplPath := ht.Table.htmlTemplate
 	if ok, _ := isValidFilePath(tmplPath); ok {
 
-		// generates new template and parse content from html and returns it
-		if tmpl, err := template.ParseFiles(tmplPath); err != nil {
-			goto tmplconst3
-		} else {
-			// if no error then return simply
-			return tmpl, err
+		// generates new template and parse content from html and returns it,
+		// fall back to default template if it can't be parsed
+		if tmpl, err := template.ParseFiles(tmplPath); err == nil {
+			return tmpl, nil
 		}
 	}
-tmplconst3:
-	// 3. Get content from constant value defined in defaults.go
-	// in case second trial failed
-	tmpl, err := template.New("gotable.tmpl").Parse(DTEMPLATE)
 
-	// finally return *Template, Error
-	return tmpl, err
+	// 4. Get the embedded default template in case other trials failed
+	return template.New("gotable.tmpl").Parse(DTEMPLATE)
 }
 
 // getCSSPropertyList returns the css property list from css map of table object
@@ -0,0 +1,414 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+)
+
+func TestHTMLEscape(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Tom & Jerry")
+	tbl.SetSection1("<b>section</b>")
+	tbl.AddColumn("Name <i>", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Link", 30, CELLSTRING, COLJUSTIFYLEFT)
+
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "<script>alert(1)</script>")
+	tbl.PutHTML(-1, 1, `<a href="https://example.com">home</a>`)
+	tbl.AddRow()
+	tbl.PutHTML(-1, 0, "<em>x</em>")
+	tbl.Puts(-1, 0, "<em>y</em>") // Puts clears the trusted html flag
+
+	if false != tbl.PutHTML(999, 999, "ignore") {
+		t.Errorf("html_test: Expected false from PutHTML out of bounds\n")
+	}
+
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := temp.String()
+
+	var expected = []string{
+		"Tom &amp; Jerry",
+		"&lt;b&gt;section&lt;/b&gt;",
+		"Name &lt;i&gt;",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<a href="https://example.com">`,
+		"&lt;em&gt;y&lt;/em&gt;",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(s, expected[i]) {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+	var unexpected = []string{"<script>", "<b>section", "<i>", "<em>"}
+	for i := 0; i < len(unexpected); i++ {
+		if strings.Contains(s, unexpected[i]) {
+			t.Errorf("html_test: Unexpected %q in output:\n%s\n", unexpected[i], s)
+		}
+	}
+}
+
+func TestHTMLFragment(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Fragment Table")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 10, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "apple")
+	tbl.Puti(-1, 1, 3)
+
+	var temp bytes.Buffer
+	if err := tbl.HTMLFragmentprintTable(&temp, "sales"); err != nil {
+		t.Errorf("html_test: Error creating HTML fragment output: %s\n", err.Error())
+	}
+	s := temp.String()
+
+	var expected = []string{
+		`<div class="rpt-table-container" id="sales">`,
+		"div#sales.rpt-table-container p.title{",
+		"div#sales.rpt-table-container table thead tr th.header-1{text-align:right;",
+		"div#sales.rpt-table-container table tbody tr td.col-0{",
+		"div#sales.rpt-table-container .container{", // scoped, can't leak into the page
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(s, expected[i]) {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+	var unexpected = []string{"<!DOCTYPE", "<title>", "html,body", "margin:0;padding:0;line-height", "div.rpt-table-container"}
+	for i := 0; i < len(unexpected); i++ {
+		if strings.Contains(s, unexpected[i]) {
+			t.Errorf("html_test: Unexpected %q in output:\n%s\n", unexpected[i], s)
+		}
+	}
+
+	// html and stylesheet separately
+	html, css, err := tbl.HTMLFragment("other")
+	if err != nil {
+		t.Errorf("html_test: Error creating HTML fragment: %s\n", err.Error())
+	}
+	if strings.Contains(html, "<style>") || !strings.Contains(html, `id="other"`) {
+		t.Errorf("html_test: Expected container only, but found:\n%s\n", html)
+	}
+	if !strings.Contains(css, "div#other.rpt-table-container table tbody tr td.col-1{") {
+		t.Errorf("html_test: Expected scoped css, but found:\n%s\n", css)
+	}
+}
+
+func TestHTMLClassCSS(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 10, CELLINT, COLJUSTIFYRIGHT)
+	for i := 0; i < 100; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "name")
+		tbl.Puti(-1, 1, int64(i))
+	}
+	tbl.SetAllCellCSS([]*CSSProperty{{Name: "background-color", Value: "yellow"}})
+	tbl.SetRowCSS(17, []*CSSProperty{{Name: "color", Value: "orange"}})
+	tbl.SetColCSS(1, []*CSSProperty{{Name: "color", Value: "blue"}})
+	tbl.SetCellCSS(3, 0, []*CSSProperty{{Name: "color", Value: "red"}})
+
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := temp.String()
+
+	// rules in the order in which they were set
+	var expected = []string{
+		"div.rpt-table-container table tbody tr.all-cells td{background-color:yellow;}",
+		"div.rpt-table-container table tbody tr.row-17 td{color:orange;}",
+		"div.rpt-table-container table tbody tr td.col-1{color:blue;}",
+		"div.rpt-table-container table tbody tr td.cell-row-3-col-0{color:red;}",
+		"div.rpt-table-container table tbody tr td.col-1{text-align:right;}",
+	}
+	last := -1
+	for i := 0; i < len(expected); i++ {
+		j := strings.Index(s, expected[i])
+		if j < 0 {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		} else if j < last {
+			t.Errorf("html_test: Expected %q after the previous rule\n", expected[i])
+		}
+		last = j
+	}
+
+	// column and table css must not fan out into per cell rules
+	if n := strings.Count(s, "cell-row-"); n != 2 {
+		t.Errorf("html_test: Expected only 1 per cell rule and class, found %d occurrences\n", n)
+	}
+	if !strings.Contains(s, `<tr class="all-cells row-17">`) || !strings.Contains(s, `<td class="col-0 cell-row-3-col-0">`) {
+		t.Errorf("html_test: Expected row and cell classes, but found:\n%s\n", s)
+	}
+}
+
+func TestHTMLCSSOrder(t *testing.T) {
+	red := []*CSSProperty{{Name: "color", Value: "red"}}
+	blue := []*CSSProperty{{Name: "color", Value: "blue"}}
+	colRule := "table tbody tr td.col-0{color:red;}"
+	rowRule := "table tbody tr.row-0 td{color:blue;}"
+	html := func(colFirst bool) string {
+		var tbl Table
+		tbl.Init() //sets column spacing and date format to default
+		tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "apple")
+		if colFirst {
+			tbl.SetColCSS(0, red)
+			tbl.SetRowCSS(0, blue)
+		} else {
+			tbl.SetRowCSS(0, blue)
+			tbl.SetColCSS(0, red)
+		}
+		var temp bytes.Buffer
+		if err := tbl.HTMLprintTable(&temp); err != nil {
+			t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+		}
+		return temp.String()
+	}
+
+	// the css set last wins, the rules are equally specific so it comes last
+	s := html(true)
+	if i, j := strings.Index(s, colRule), strings.Index(s, rowRule); i < 0 || j < i {
+		t.Errorf("html_test: Expected row rule after column rule, found:\n%s\n", s)
+	}
+	s = html(false)
+	if i, j := strings.Index(s, rowRule), strings.Index(s, colRule); i < 0 || j < i {
+		t.Errorf("html_test: Expected column rule after row rule, found:\n%s\n", s)
+	}
+}
+
+func TestHTMLCSSRowsAddedLater(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "apple")
+	tbl.SetAllCellCSS([]*CSSProperty{{Name: "background-color", Value: "yellow"}})
+	tbl.SetColCSS(0, []*CSSProperty{{Name: "color", Value: "red"}})
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "pear")
+	tbl.SetColCSS(0, []*CSSProperty{{Name: "font-weight", Value: "bold"}})
+
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := temp.String()
+
+	// css set before a row was added doesn't apply to it
+	var expected = []string{
+		`<tr class="all-cells"> <td class="col-0"> apple </td> </tr>`,
+		`<tr> <td class="col-0-part-1"> pear </td> </tr>`,
+		"table tbody tr td.col-0-part-1{font-weight:bold;}",
+		"table tbody tr td.col-0-part-1{text-align:left;}",
+	}
+	o := strings.Join(strings.Fields(s), " ")
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(o, expected[i]) {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+	if strings.Contains(s, "col-0-part-1{color") || strings.Count(s, "background-color:yellow") != 1 {
+		t.Errorf("html_test: Expected css set before the row was added not to apply to it, found:\n%s\n", s)
+	}
+
+	// the order survives json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("html_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("html_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	var temp2 bytes.Buffer
+	temp.Reset()
+	tbl.HTMLprintTable(&temp)
+	tbl2.HTMLprintTable(&temp2)
+	if temp.String() != temp2.String() {
+		t.Errorf("html_test: Expected:\n%s\nfound:\n%s\n", temp.String(), temp2.String())
+	}
+}
+
+func TestHTMLTemplateFS(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Template Table")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "apple")
+
+	// embedded defaults are always available
+	if !strings.Contains(DCSS, TABLECONTAINERCLASS) || !strings.Contains(DTEMPLATE, "{{.TableHTML}}") {
+		t.Errorf("html_test: Expected embedded default css and template\n")
+	}
+
+	fsys := fstest.MapFS{
+		"tmpl/report.tmpl": {Data: []byte(`<html><body class="report">{{.CustomCSS}}{{.TableHTML}}</body></html>`)},
+		"tmpl/report.css":  {Data: []byte(`p.title{color:purple} body{margin:1px} /* {note} */ table td, .mycls{color:teal}@media print{td.x{color:gray}}`)},
+		"tmpl/bad.css":     {Data: []byte(`@media print{td{color:gray}`)},
+		"tmpl/broken.tmpl": {Data: []byte(`{{.TableHTML`)},
+	}
+	if err := tbl.SetHTMLTemplateFS(nil, "tmpl/report.tmpl", ""); err == nil {
+		t.Errorf("html_test: Expected error for nil file system\n")
+	}
+	if err := tbl.SetHTMLTemplateFS(fsys, "tmpl/missing.tmpl", ""); err == nil {
+		t.Errorf("html_test: Expected error for missing template\n")
+	}
+	if err := tbl.SetHTMLTemplateFS(fsys, "tmpl/broken.tmpl", ""); err == nil {
+		t.Errorf("html_test: Expected error for broken template\n")
+	}
+	if err := tbl.SetHTMLTemplateFS(fsys, "tmpl/report.tmpl", "tmpl/missing.css"); err == nil {
+		t.Errorf("html_test: Expected error for missing css\n")
+	}
+	if err := tbl.SetHTMLTemplateFS(fsys, "tmpl/report.tmpl", "tmpl/report.css"); err != nil {
+		t.Errorf("html_test: Error setting template file system: %s\n", err.Error())
+	}
+
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	if s := temp.String(); !strings.Contains(s, `<body class="report">`) || strings.Contains(s, "<!DOCTYPE") {
+		t.Errorf("html_test: Expected template from file system, but found:\n%s\n", s)
+	}
+
+	// css is read from file system too, fragment has no page template
+	_, css, err := tbl.HTMLFragment("")
+	if err != nil {
+		t.Errorf("html_test: Error creating HTML fragment: %s\n", err.Error())
+	}
+	if strings.Contains(css, "font-weight:bold") || strings.Contains(css, "margin:1px") {
+		t.Errorf("html_test: Expected css from file system, but found:\n%s\n", css)
+	}
+	// custom rules are scoped by the container, not dropped
+	for _, e := range []string{
+		`div.rpt-table-container p.title{color:purple}`,
+		`div.rpt-table-container table td,div.rpt-table-container .mycls{color:teal}`,
+		`@media print{div.rpt-table-container td.x{color:gray}}`,
+	} {
+		if !strings.Contains(css, e) {
+			t.Errorf("html_test: Expected %q in fragment css, but found:\n%s\n", e, css)
+		}
+	}
+	if _, css, _ := tbl.HTMLFragment("report"); !strings.Contains(css, `div#report.rpt-table-container .mycls{color:teal}`) {
+		t.Errorf("html_test: Expected css scoped by id, but found:\n%s\n", css)
+	}
+	tbl.SetHTMLTemplateFS(fsys, "tmpl/report.tmpl", "tmpl/bad.css")
+	if _, _, err := tbl.HTMLFragment(""); err == nil {
+		t.Errorf("html_test: Expected error for unbalanced css\n")
+	}
+	tbl.SetHTMLTemplateFS(fsys, "tmpl/report.tmpl", "tmpl/report.css")
+
+	// parsed template takes precedence
+	tmpl := template.Must(template.New("custom").Funcs(template.FuncMap{
+		"upper": strings.ToUpper,
+	}).Parse(`<main>{{upper .HeadTitle}}</main>{{.TableHTML}}`))
+	tbl.SetHTMLTemplateParsed(tmpl)
+
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	if s := temp.String(); !strings.Contains(s, "TEMPLATE TABLE") || strings.Contains(s, `class="report"`) {
+		t.Errorf("html_test: Expected parsed template, but found:\n%s\n", s)
+	}
+}
+
+func TestHTMLInteractive(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	for i := 0; i < 3; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "name")
+		tbl.Putf(-1, 1, 1234.5*float64(i))
+	}
+	tbl.AddLineAfter(tbl.RowCount() - 1)
+	tbl.InsertSumRow(-1, 0, tbl.RowCount()-1, []int{1})
+
+	// not interactive by default
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	if s := temp.String(); strings.Contains(s, "<script>") || strings.Contains(s, "data-v") {
+		t.Errorf("html_test: Unexpected interactive output:\n%s\n", s)
+	}
+
+	tbl.SetHTMLInteractive(HTMLInteractiveOptions{Sort: true, Filter: true, PageSize: 25})
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := temp.String()
+	var expected = []string{
+		`data-type="str"`,
+		`data-type="num"`,
+		`data-v="2469"`,
+		`data-v="3703.5"`,
+		`({"sort":true,"filter":true,"pageSize":25});`,
+		"th.sort-asc:after{",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(s, expected[i]) {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+	// sum row is in its own group
+	if n := strings.Count(s, "<tbody>"); n != 2 {
+		t.Errorf("html_test: Expected 2 row groups, found %d\n", n)
+	}
+	// script is inside the table container
+	if i, j := strings.Index(s, "<script>"), strings.LastIndex(s, "</div>\n</div>"); i < 0 || i > j {
+		t.Errorf("html_test: Expected script inside table container, but found:\n%s\n", s)
+	}
+
+	// cells are found by their column, merged cells span columns
+	tbl.InsertColumn(2, "Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.Puti(0, 2, 7)
+	tbl.Puti(1, 2, 3)
+	tbl.MergeCells(1, 0, 1, 2)
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s = temp.String()
+	for _, e := range []string{`data-col="0" colspan="2">`, `data-col="2" data-v="3"`, `data-col="1" data-v="0"`, `data-col="2" data-v="7"`} {
+		if !strings.Contains(s, e) {
+			t.Errorf("html_test: Expected %q in output, but found:\n%s\n", e, s)
+		}
+	}
+	if strings.Contains(s, `data-col="1" data-v="1234.5"`) {
+		t.Errorf("html_test: Unexpected cell covered by merge in output:\n%s\n", s)
+	}
+	tbl.UnmergeCells(1, 0)
+	tbl.DeleteColumn(2)
+
+	// pdf is never interactive, table is left untouched
+	var pdfTable = &PDFTable{Table: &tbl, Options: DefaultPDFOptions()}
+	if args := pdfTable.getWkhtmltopdfArgs(); len(args) == 0 || tbl.htmlInteractive == nil {
+		t.Errorf("html_test: Expected interactive option to be kept on table\n")
+	}
+
+	tbl.SetHTMLInteractive(HTMLInteractiveOptions{})
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("html_test: Error creating HTML output: %s\n", err.Error())
+	}
+	if strings.Contains(temp.String(), "<script>") {
+		t.Errorf("html_test: Expected interactive output to be turned off\n")
+	}
+}
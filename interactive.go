@@ -0,0 +1,95 @@
+package gotable
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// HTMLInteractiveOptions holds the options of interactive html output. The
+// interactivity comes from a small script inlined in the table container,
+// nothing is loaded from the network.
+type HTMLInteractiveOptions struct {
+	Sort     bool `json:"sort"`     // click on a column header to sort rows by the column
+	Filter   bool `json:"filter"`   // text box to show only the rows containing the text
+	PageSize int  `json:"pageSize"` // rows per page, 0 for no paging
+}
+
+// interactiveJS is the script of interactive html output, it is a function
+// expression which is called with HTMLInteractiveOptions
+var interactiveJS = mustReadAsset("assets/interactive.js")
+
+// SetHTMLInteractive turns interactive html output on, zero options turn it
+// off. Rows are sorted by their typed values, not by the formatted text.
+// Rows between separator lines (LineBefore, LineAfter) form a group, e.g.
+// the rows above a subtotal, sorting never moves a row out of its group and
+// the separator lines stay in place. Pdf output is never interactive.
+func (t *Table) SetHTMLInteractive(opts HTMLInteractiveOptions) {
+	if opts == (HTMLInteractiveOptions{}) {
+		t.htmlInteractive = nil
+		return
+	}
+	t.htmlInteractive = &opts
+}
+
+// isInteractive reports whether the html output is interactive
+func (ht *HTMLTable) isInteractive() bool {
+	return ht.Table.htmlInteractive != nil
+}
+
+// getInteractiveScript returns the script tag which makes the table interactive,
+// it must be placed inside the table container
+func (ht *HTMLTable) getInteractiveScript() string {
+	opts, _ := json.Marshal(ht.Table.htmlInteractive)
+	return `<script>(` + interactiveJS + `)(` + string(opts) + `);</script>`
+}
+
+// getInteractiveCSS returns the css of the controls and sortable headers
+func (ht *HTMLTable) getInteractiveCSS() string {
+	c := ht.getContainerSelector()
+	return c + ` div.interactive-controls{margin:0.5em 0;text-align:right}` +
+		c + ` div.interactive-controls span{margin:0 0.5em}` +
+		c + ` table thead tr th.sortable{cursor:pointer}` +
+		c + ` table thead tr th.sort-asc:after{content:" \25B2"}` +
+		c + ` table thead tr th.sort-desc:after{content:" \25BC"}`
+}
+
+// getInteractiveType returns the data-type attribute of a header cell, numbers,
+// dates and datetimes are sorted by the typed value of the cells
+func (ht *HTMLTable) getInteractiveType(colIndex int) string {
+	if !ht.isInteractive() {
+		return ""
+	}
+	switch ht.Table.ColDefs[colIndex].CellType {
+	case CELLINT, CELLFLOAT, CELLDATE, CELLDATETIME:
+		return ` data-type="num"`
+	}
+	return ` data-type="str"`
+}
+
+// getInteractiveValue returns the data-col attribute of a cell in column col,
+// merges make the index of a cell in its row differ from its column, and the
+// data-v attribute holding its typed value
+func (ht *HTMLTable) getInteractiveValue(c Cell, col int) string {
+	if !ht.isInteractive() {
+		return ""
+	}
+	attrs := ` data-col="` + strconv.Itoa(col) + `"`
+	switch c.Type {
+	case CELLINT:
+		attrs += ` data-v="` + strconv.FormatInt(c.Ival, 10) + `"`
+	case CELLFLOAT:
+		if !math.IsNaN(c.Fval) && !math.IsInf(c.Fval, 0) {
+			attrs += ` data-v="` + strconv.FormatFloat(c.Fval, 'g', -1, 64) + `"`
+		}
+	case CELLDATE, CELLDATETIME:
+		attrs += ` data-v="` + strconv.FormatInt(c.Dval.Unix(), 10) + `"`
+	}
+	return attrs
+}
+
+// isGroupStart reports whether a row starts a new group of rows in interactive
+// output, groups are separated by lines
+func (ht *HTMLTable) isGroupStart(rowIndex int) bool {
+	return rowIndex > 0 && (ht.Table.hasLineBefore(rowIndex) || ht.Table.hasLineAfter(rowIndex-1))
+}
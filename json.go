@@ -0,0 +1,454 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONROWOBJECT et. al. are the constants used to select the row layout of json output
+const (
+	JSONROWOBJECT = 1 // each row is an object keyed by column key or title, see jsonRowKeys
+	JSONROWARRAY  = 2 // each row is an array of values ordered as the columns
+
+	JSONDATEFMT = "2006-01-02" // RFC 3339 full-date, used for CELLDATE values
+)
+
+// JSONTable struct used to prepare table in json version
+type JSONTable struct {
+	*Table
+	RowLayout int
+	outbuf    bytes.Buffer
+	keys      []string // object keys of the columns
+}
+
+// jsonColumn describes one column definition in json output
+type jsonColumn struct {
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Justify string `json:"justify"`
+	Width   int    `json:"width"`
+}
+
+func (jt *JSONTable) writeTableOutput(w io.Writer) error {
+	var parts []string
+
+	// append title and sections
+	parts = append(parts, `"title":`+jt.getTitle())
+	parts = append(parts, `"section1":`+jt.getSection1())
+	parts = append(parts, `"section2":`+jt.getSection2())
+	parts = append(parts, `"section3":`+jt.getSection3())
+
+	// append headers
+	if headerStr, err := jt.getHeaders(); err != nil {
+		parts = append(parts, `"columns":[]`, `"rows":[]`, `"error":`+jsonString(err.Error()))
+	} else {
+		parts = append(parts, `"columns":`+headerStr)
+		if len(jt.Table.HeaderGroups) > 0 {
+			b, _ := json.Marshal(jt.Table.HeaderGroups)
+			parts = append(parts, `"headerGroups":`+string(b))
+		}
+
+		// append rows
+		if rowsStr, err := jt.getRows(); err != nil {
+			parts = append(parts, `"rows":[]`, `"error":`+jsonString(err.Error()))
+			if len(jt.Table.Footer) > 0 {
+				parts = append(parts, `"footer":`+jt.getFooter())
+			}
+		} else {
+			parts = append(parts, `"rows":`+rowsStr)
+			if len(jt.Table.Footer) > 0 {
+				parts = append(parts, `"footer":`+jt.getFooter())
+			}
+			if len(jt.Table.Merges) > 0 {
+				b, _ := json.Marshal(jt.Table.Merges)
+				parts = append(parts, `"merges":`+string(b))
+			}
+		}
+	}
+
+	// indent the output so that it is readable by human as well
+	if err := json.Indent(&jt.outbuf, []byte(`{`+strings.Join(parts, `,`)+`}`), "", "  "); err != nil {
+		return err
+	}
+	jt.outbuf.WriteString(NEWLINE)
+
+	// write output to passed io.Writer interface object
+	_, err := w.Write(jt.outbuf.Bytes())
+	return err
+}
+
+func (jt *JSONTable) getTitle() string {
+	return jsonString(jt.Table.GetTitle())
+}
+
+func (jt *JSONTable) getSection1() string {
+	return jsonString(jt.Table.GetSection1())
+}
+
+func (jt *JSONTable) getSection2() string {
+	return jsonString(jt.Table.GetSection2())
+}
+
+func (jt *JSONTable) getSection3() string {
+	return jsonString(jt.Table.GetSection3())
+}
+
+func (jt *JSONTable) getHeaders() (string, error) {
+	// check for blank headers
+	blankHdrsErr := jt.Table.HasHeaders()
+	if blankHdrsErr != nil {
+		return "", blankHdrsErr
+	}
+
+	var cols []jsonColumn
+	for i := 0; i < len(jt.Table.ColDefs); i++ {
+		cd := jt.Table.ColDefs[i]
+		jc := jsonColumn{Key: jt.rowKeys()[i], Title: cd.ColTitle, Type: cellTypeName(cd.CellType), Width: cd.Width}
+		switch cd.Justify {
+		case COLJUSTIFYLEFT:
+			jc.Justify = "left"
+		case COLJUSTIFYRIGHT:
+			jc.Justify = "right"
+		}
+		cols = append(cols, jc)
+	}
+
+	b, err := json.Marshal(cols)
+	return string(b), err
+}
+
+func (jt *JSONTable) getRows() (string, error) {
+	// check for empty data table
+	blankDataErr := jt.Table.HasData()
+	if blankDataErr != nil {
+		return "", blankDataErr
+	}
+
+	var rows []string
+	for i := 0; i < jt.Table.RowCount(); i++ {
+		// for valid row, we will never get an error
+		s, _ := jt.getRow(i)
+		rows = append(rows, s)
+	}
+
+	return `[` + strings.Join(rows, `,`) + `]`, nil
+}
+
+func (jt *JSONTable) getRow(row int) (string, error) {
+	return jt.getColset(jt.Table.Row[row]), nil
+}
+
+// getFooter returns the footer rows in the same layout as the rows
+func (jt *JSONTable) getFooter() string {
+	var rows []string
+	for i := 0; i < len(jt.Table.Footer); i++ {
+		rows = append(rows, jt.getColset(jt.Table.getFooterRow(i)))
+	}
+	return `[` + strings.Join(rows, `,`) + `]`
+}
+
+// getColset returns json encoded row of cells
+func (jt *JSONTable) getColset(cs Colset) string {
+	var tRow []string
+
+	for i := 0; i < len(cs.Col); i++ {
+		v := jt.getCellValue(cs.Col[i])
+		if jt.RowLayout == JSONROWARRAY {
+			tRow = append(tRow, v)
+		} else {
+			// object keys are emitted by hand so that the column order is preserved
+			tRow = append(tRow, jsonString(jt.rowKeys()[i])+`:`+v)
+		}
+	}
+
+	if jt.RowLayout == JSONROWARRAY {
+		return `[` + strings.Join(tRow, `,`) + `]`
+	}
+	return `{` + strings.Join(tRow, `,`) + `}`
+}
+
+// rowKeys returns the object keys of the columns, see jsonRowKeys
+func (jt *JSONTable) rowKeys() []string {
+	if len(jt.keys) != len(jt.Table.ColDefs) {
+		jt.keys = jsonRowKeys(jt.Table.ColDefs)
+	}
+	return jt.keys
+}
+
+// jsonRowKeys returns unique object keys for the columns cds. The key of a
+// column is its Key if set, else its title. Titles which are used already get
+// a suffix, "_2", "_3" and so on.
+func jsonRowKeys(cds []ColumnDef) []string {
+	keys := make([]string, len(cds))
+	used := map[string]bool{}
+	for i := range cds {
+		if cds[i].Key != "" {
+			keys[i] = cds[i].Key
+			used[keys[i]] = true
+		}
+	}
+	for i := range cds {
+		if keys[i] != "" {
+			continue
+		}
+		key := cds[i].ColTitle
+		for n := 2; used[key]; n++ {
+			key = fmt.Sprintf("%s_%d", cds[i].ColTitle, n)
+		}
+		keys[i] = key
+		used[key] = true
+	}
+	return keys
+}
+
+// checkJSONLayout returns an error if layout is not a valid row layout
+func checkJSONLayout(layout int) error {
+	if layout != JSONROWOBJECT && layout != JSONROWARRAY {
+		return fmt.Errorf("Invalid json row layout: %d", layout)
+	}
+	return nil
+}
+
+// getCellValue returns json encoded native value of the cell
+func (jt *JSONTable) getCellValue(c Cell) string {
+	var v interface{}
+
+	switch c.Type {
+	case CELLINT:
+		v = c.Ival
+	case CELLFLOAT:
+		// json has no representation for NaN and infinity
+		if !math.IsNaN(c.Fval) && !math.IsInf(c.Fval, 0) {
+			v = c.Fval
+		}
+	case CELLSTRING:
+		v = c.Sval
+	case CELLDATE:
+		v = c.Dval.Format(JSONDATEFMT)
+	case CELLDATETIME:
+		v = c.Dval.Format(time.RFC3339)
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// jsonString returns json encoded string
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ==========================
+// Table JSON (un)marshaling
+// ==========================
+
+// tableJSON holds every property of Table, including the unexported ones,
+// so that a table survives a json round-trip without losing anything
+type tableJSON struct {
+	Title           string                             `json:"title"`
+	Section1        string                             `json:"section1"`
+	Section2        string                             `json:"section2"`
+	Section3        string                             `json:"section3"`
+	ColDefs         []ColumnDef                        `json:"colDefs"`
+	HeaderGroups    []HeaderGroup                      `json:"headerGroups,omitempty"`
+	Row             []Colset                           `json:"rows"`
+	Footer          []Colset                           `json:"footer,omitempty"`
+	FooterAggs      map[int]string                     `json:"footerAggregates,omitempty"`
+	MaxHdrRows      int                                `json:"maxHdrRows"`
+	DateFmt         string                             `json:"dateFmt"`
+	DateTimeFmt     string                             `json:"dateTimeFmt"`
+	LineAfter       []int                              `json:"lineAfter"`
+	LineBefore      []int                              `json:"lineBefore"`
+	RS              []Rowset                           `json:"rowsets"`
+	Merges          []CellMerge                        `json:"merges,omitempty"`
+	CSS             map[string]map[string]*CSSProperty `json:"css"`
+	CSSSeq          int                                `json:"cssSeq,omitempty"`
+	CSSOrder        map[string]map[string]int          `json:"cssOrder,omitempty"`  // sequence numbers of css properties
+	RowCSSSeq       []int                              `json:"rowCssSeq,omitempty"` // css sequence numbers of rows
+	HTMLTemplate    string                             `json:"htmlTemplate"`
+	HTMLTemplateCSS string                             `json:"htmlTemplateCSS"`
+	Theme           *Theme                             `json:"theme,omitempty"`
+	HTMLInteractive *HTMLInteractiveOptions            `json:"htmlInteractive,omitempty"`
+	CSVMergeRepeat  bool                               `json:"csvMergeRepeat,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface. Unlike JSONprintTable, which
+// is meant for the consumers of a report, it encodes the complete state of the
+// table so that it can be restored by UnmarshalJSON
+func (t Table) MarshalJSON() ([]byte, error) {
+	tj := tableJSON{
+		Title:           t.Title,
+		Section1:        t.Section1,
+		Section2:        t.Section2,
+		Section3:        t.Section3,
+		ColDefs:         t.ColDefs,
+		HeaderGroups:    t.HeaderGroups,
+		Row:             t.Row,
+		Footer:          t.Footer,
+		MaxHdrRows:      t.maxHdrRows,
+		DateFmt:         t.DateFmt,
+		DateTimeFmt:     t.DateTimeFmt,
+		LineAfter:       t.LineAfter,
+		LineBefore:      t.LineBefore,
+		RS:              t.RS,
+		Merges:          t.Merges,
+		CSS:             t.CSS,
+		HTMLTemplate:    t.htmlTemplate,
+		HTMLTemplateCSS: t.htmlTemplateCSS,
+		Theme:           t.theme,
+		HTMLInteractive: t.htmlInteractive,
+		CSVMergeRepeat:  t.csvMergeRepeat,
+	}
+	// aggregates are stored by name, only built-in and registered ones can be restored
+	for col, agg := range t.footerAggs {
+		if tj.FooterAggs == nil {
+			tj.FooterAggs = make(map[int]string)
+		}
+		tj.FooterAggs[col] = agg.Name()
+	}
+	// order in which cell css was set, see setCSS
+	if t.cssSeq > 0 {
+		tj.CSSSeq = t.cssSeq
+		tj.CSSOrder = make(map[string]map[string]int)
+		for key, cssMap := range t.CSS {
+			for name, cssProp := range cssMap {
+				if cssProp.seq > 0 {
+					if tj.CSSOrder[key] == nil {
+						tj.CSSOrder[key] = make(map[string]int)
+					}
+					tj.CSSOrder[key][name] = cssProp.seq
+				}
+			}
+		}
+		tj.RowCSSSeq = make([]int, len(t.Row))
+		for i := range t.Row {
+			tj.RowCSSSeq[i] = t.Row[i].cssSeq
+		}
+	}
+	return json.Marshal(tj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface, it restores a table
+// encoded by MarshalJSON
+func (t *Table) UnmarshalJSON(b []byte) error {
+	var tj tableJSON
+	if err := json.Unmarshal(b, &tj); err != nil {
+		return err
+	}
+
+	t.Title = tj.Title
+	t.Section1 = tj.Section1
+	t.Section2 = tj.Section2
+	t.Section3 = tj.Section3
+	t.ColDefs = tj.ColDefs
+	t.HeaderGroups = tj.HeaderGroups
+	t.Row = tj.Row
+	t.Footer = tj.Footer
+	t.footerAggs = nil
+	for col, name := range tj.FooterAggs {
+		if agg, ok := lookupAggFunc(name); ok {
+			if t.footerAggs == nil {
+				t.footerAggs = make(map[int]AggFunc)
+			}
+			t.footerAggs[col] = agg
+		}
+	}
+	t.maxHdrRows = tj.MaxHdrRows
+	t.DateFmt = tj.DateFmt
+	t.DateTimeFmt = tj.DateTimeFmt
+	t.LineAfter = tj.LineAfter
+	t.LineBefore = tj.LineBefore
+	t.RS = tj.RS
+	t.Merges = tj.Merges
+	t.CSS = tj.CSS
+	t.htmlTemplate = tj.HTMLTemplate
+	t.htmlTemplateCSS = tj.HTMLTemplateCSS
+	t.theme = tj.Theme
+	t.htmlInteractive = tj.HTMLInteractive
+	t.csvMergeRepeat = tj.CSVMergeRepeat
+
+	// css map must be usable right after decoding, same as after Init
+	if t.CSS == nil {
+		t.CSS = make(map[string]map[string]*CSSProperty)
+	}
+	t.cssSeq = tj.CSSSeq
+	for key, order := range tj.CSSOrder {
+		for name, seq := range order {
+			if cssProp, ok := t.CSS[key][name]; ok && cssProp != nil {
+				cssProp.seq = seq
+			}
+		}
+	}
+	if len(tj.RowCSSSeq) == len(t.Row) {
+		for i := range t.Row {
+			t.Row[i].cssSeq = tj.RowCSSSeq[i]
+		}
+	}
+	return nil
+}
+
+// cellAlias is Cell without its json methods
+type cellAlias Cell
+
+// MarshalJSON implements json.Marshaler interface. Json has no representation
+// for NaN and infinity, so a float value which is not finite is encoded as a
+// string, "NaN", "+Inf" or "-Inf", UnmarshalJSON decodes it back.
+func (c Cell) MarshalJSON() ([]byte, error) {
+	if !math.IsNaN(c.Fval) && !math.IsInf(c.Fval, 0) {
+		return json.Marshal(cellAlias(c))
+	}
+	return json.Marshal(struct {
+		cellAlias
+		Fval string
+	}{cellAlias(c), strconv.FormatFloat(c.Fval, 'g', -1, 64)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface, it decodes a cell
+// encoded by MarshalJSON
+func (c *Cell) UnmarshalJSON(b []byte) error {
+	cj := struct {
+		*cellAlias
+		Fval json.RawMessage
+	}{cellAlias: (*cellAlias)(c)}
+	if err := json.Unmarshal(b, &cj); err != nil {
+		return err
+	}
+	if len(cj.Fval) == 0 {
+		return nil
+	}
+	if cj.Fval[0] != '"' {
+		return json.Unmarshal(cj.Fval, &c.Fval)
+	}
+	var s string
+	if err := json.Unmarshal(cj.Fval, &s); err != nil {
+		return err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid float value of cell: %s", s)
+	}
+	c.Fval = f
+	return nil
+}
+
+// MarshalTable returns the json encoding of the complete table
+func MarshalTable(t *Table) ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// UnmarshalTable decodes a table encoded by MarshalTable
+func UnmarshalTable(b []byte) (*Table, error) {
+	var t Table
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
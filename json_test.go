@@ -0,0 +1,200 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("JSON Table")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Date", 10, CELLDATE, COLJUSTIFYLEFT)
+
+	dt := time.Date(2017, time.February, 21, 0, 0, 0, 0, time.UTC)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "first")
+	tbl.Puti(-1, 1, 3)
+	tbl.Putf(-1, 2, 12345.678)
+	tbl.Putd(-1, 3, dt)
+	tbl.AddRow() // all empty cells
+
+	// object per row layout
+	var temp bytes.Buffer
+	if err := tbl.JSONprintTable(&temp); err != nil {
+		t.Errorf("json_test: Error creating JSON output: %s\n", err.Error())
+	}
+	var out struct {
+		Title   string
+		Columns []map[string]interface{}
+		Rows    []map[string]interface{}
+	}
+	if err := json.Unmarshal(temp.Bytes(), &out); err != nil {
+		t.Fatalf("json_test: Error decoding JSON output: %s\n%s\n", err.Error(), temp.String())
+	}
+	if out.Title != "JSON Table" || len(out.Columns) != 4 || len(out.Rows) != 2 {
+		t.Errorf("json_test: Unexpected output: %s\n", temp.String())
+	}
+	if out.Columns[2]["type"] != "float" || out.Columns[2]["justify"] != "right" {
+		t.Errorf("json_test: Unexpected column definition: %#v\n", out.Columns[2])
+	}
+	if out.Rows[0]["Amount"] != 12345.678 || out.Rows[0]["Count"] != float64(3) {
+		t.Errorf("json_test: Expected native numbers, found: %#v\n", out.Rows[0])
+	}
+	if out.Rows[0]["Date"] != "2017-02-21" {
+		t.Errorf("json_test: Expected RFC 3339 date, found: %#v\n", out.Rows[0]["Date"])
+	}
+	if out.Rows[1]["Name"] != nil {
+		t.Errorf("json_test: Expected null for empty cell, found: %#v\n", out.Rows[1]["Name"])
+	}
+
+	// array per row layout
+	temp.Reset()
+	if err := tbl.JSONprintTableLayout(&temp, JSONROWARRAY); err != nil {
+		t.Errorf("json_test: Error creating JSON output: %s\n", err.Error())
+	}
+	var outArr struct {
+		Rows [][]interface{}
+	}
+	if err := json.Unmarshal(temp.Bytes(), &outArr); err != nil {
+		t.Fatalf("json_test: Error decoding JSON output: %s\n%s\n", err.Error(), temp.String())
+	}
+	if len(outArr.Rows) != 2 || outArr.Rows[0][0] != "first" || outArr.Rows[0][2] != 12345.678 {
+		t.Errorf("json_test: Unexpected array layout: %s\n", temp.String())
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.DateFmt = "2006-01-02"
+	tbl.SetTitle("Round Trip")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("When", 25, CELLDATETIME, COLJUSTIFYLEFT)
+
+	rsid := tbl.CreateRowset()
+	for i := 0; i < 3; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "row")
+		tbl.Putf(-1, 1, float64(i)+0.25)
+		tbl.Putdt(-1, 2, time.Date(2017, time.February, 21+i, 10, 30, 0, 0, time.UTC))
+		tbl.AppendToRowset(rsid, i)
+	}
+	tbl.AddLineAfter(2)
+	tbl.AddLineBefore(1)
+	tbl.SetColCSS(1, []*CSSProperty{{Name: "color", Value: "red"}})
+	tbl.AdjustAllColumnHeaders()
+	tbl.htmlTemplate = "custom.tmpl"
+	tbl.htmlTemplateCSS = "custom.css"
+
+	b, err := MarshalTable(&tbl)
+	if err != nil {
+		t.Fatalf("json_test: Error marshaling table: %s\n", err.Error())
+	}
+	tbl2, err := UnmarshalTable(b)
+	if err != nil {
+		t.Fatalf("json_test: Error unmarshaling table: %s\n", err.Error())
+	}
+
+	if tbl.String() != tbl2.String() {
+		t.Errorf("json_test: Expected:\n%s\nfound:\n%s\n", tbl.String(), tbl2.String())
+	}
+	if tbl2.maxHdrRows != tbl.maxHdrRows || tbl2.htmlTemplate != tbl.htmlTemplate || tbl2.htmlTemplateCSS != tbl.htmlTemplateCSS {
+		t.Errorf("json_test: unexported fields are lost: %#v\n", tbl2)
+	}
+	if !compareIntSlices(tbl2.GetRowset(rsid), tbl.GetRowset(rsid)) {
+		t.Errorf("json_test: Expected rowset %#v, found %#v\n", tbl.GetRowset(rsid), tbl2.GetRowset(rsid))
+	}
+	if tbl2.Get(1, 2).Type != CELLDATETIME || !tbl2.Getd(1, 2).Equal(tbl.Getd(1, 2)) {
+		t.Errorf("json_test: Expected cell %#v, found %#v\n", tbl.Get(1, 2), tbl2.Get(1, 2))
+	}
+	if css, ok := tbl2.CSS[tbl2.getCSSMapKeyForCol(1)]; !ok || css["color"].Value != "red" {
+		t.Errorf("json_test: Expected css to be preserved, found %#v\n", tbl2.CSS)
+	}
+}
+
+func TestJSONRowKeys(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumnWithKey("Amount_2", "Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumnWithKey("total", "Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	for i := 0; i < 4; i++ {
+		tbl.Putf(-1, i, float64(i+1))
+	}
+
+	// keys are used first, titles are made unique
+	var temp bytes.Buffer
+	if err := tbl.JSONprintTable(&temp); err != nil {
+		t.Fatalf("json_test: Error creating JSON output: %s\n", err.Error())
+	}
+	var out struct {
+		Columns []map[string]interface{}
+		Rows    []map[string]interface{}
+	}
+	if err := json.Unmarshal(temp.Bytes(), &out); err != nil {
+		t.Fatalf("json_test: Error decoding JSON output: %s\n%s\n", err.Error(), temp.String())
+	}
+	expect := map[string]interface{}{"Amount": 1.0, "Amount_3": 2.0, "Amount_2": 3.0, "total": 4.0}
+	if len(out.Rows) != 1 || !reflect.DeepEqual(out.Rows[0], expect) {
+		t.Errorf("json_test: Expected row %v, found:\n%s\n", expect, temp.String())
+	}
+	if out.Columns[1]["key"] != "Amount_3" || out.Columns[1]["title"] != "Amount" {
+		t.Errorf("json_test: Expected key of column in column definition, found %v\n", out.Columns[1])
+	}
+
+	for _, layout := range []int{0, 3} {
+		if err := tbl.JSONprintTableLayout(&temp, layout); err == nil {
+			t.Errorf("json_test: Expected error for row layout %d\n", layout)
+		}
+		if err := tbl.Export(&temp, FORMATJSON, layout); err == nil {
+			t.Errorf("json_test: Expected error exporting row layout %d\n", layout)
+		}
+	}
+}
+
+func TestJSONRoundTripNonFinite(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	values := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1.5}
+	for _, f := range values {
+		tbl.AddRow()
+		tbl.Putf(-1, 0, f)
+	}
+
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("json_test: Error marshaling table: %s\n", err.Error())
+	}
+	if !bytes.Contains(b, []byte(`"Fval":"NaN"`)) || !bytes.Contains(b, []byte(`"Fval":1.5`)) {
+		t.Errorf("json_test: Unexpected encoding of float cells: %s\n", b)
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("json_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if !math.IsNaN(tbl2.Getf(0, 0)) {
+		t.Errorf("json_test: Expected NaN, found %v\n", tbl2.Get(0, 0))
+	}
+	for row := 1; row < len(values); row++ {
+		if c := tbl2.Get(row, 0); c.Type != CELLFLOAT || c.Fval != values[row] {
+			t.Errorf("json_test: Expected %v in row %d, found %v\n", values[row], row, c)
+		}
+	}
+
+	var c Cell
+	if err := json.Unmarshal([]byte(`{"Type":2,"Fval":"abc"}`), &c); err == nil {
+		t.Errorf("json_test: Expected error decoding invalid float value\n")
+	}
+}
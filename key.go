@@ -0,0 +1,257 @@
+package gotable
+
+import (
+	"fmt"
+	"time"
+)
+
+// AddColumnWithKey adds a column like AddColumn and gives it a key, a name
+// which stays with the column when columns are inserted, deleted or moved.
+// The ...ByKey methods address columns by their key. Keys must be unique.
+func (t *Table) AddColumnWithKey(key, title string, width, celltype int, justification int) error {
+	if err := t.checkColumnKey(key); err != nil {
+		return err
+	}
+	t.AddColumn(title, width, celltype, justification)
+	t.ColDefs[len(t.ColDefs)-1].Key = key
+	return nil
+}
+
+// SetColumnKey sets the key of the column at index col, a blank key removes it
+func (t *Table) SetColumnKey(col int, key string) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	if key != "" && key != t.ColDefs[col].Key {
+		if err := t.checkColumnKey(key); err != nil {
+			return err
+		}
+	}
+	t.ColDefs[col].Key = key
+	return nil
+}
+
+// checkColumnKey returns an error if key is blank or already in use
+func (t *Table) checkColumnKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("Column key is blank")
+	}
+	if _, err := t.ColumnIndex(key); err == nil {
+		return fmt.Errorf("Column key is already in use, key: %s", key)
+	}
+	return nil
+}
+
+// ColumnIndex returns the index of the column with the supplied key
+func (t *Table) ColumnIndex(key string) (int, error) {
+	if key != "" {
+		for i := range t.ColDefs {
+			if t.ColDefs[i].Key == key {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("Unknown column key: %s", key)
+}
+
+// ColumnIndexes returns the indexes of the columns with the supplied keys
+func (t *Table) ColumnIndexes(keys ...string) ([]int, error) {
+	cols := make([]int, len(keys))
+	for i, key := range keys {
+		col, err := t.ColumnIndex(key)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// GetByKey returns the cell at row of the column with the supplied key
+func (t *Table) GetByKey(row int, key string) (Cell, error) {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return Cell{}, err
+	}
+	if err := t.HasValidRow(row); err != nil {
+		return Cell{}, err
+	}
+	return t.Get(row, col), nil
+}
+
+// GetiByKey returns the int value of the cell at row of the column with the supplied key
+func (t *Table) GetiByKey(row int, key string) (int64, error) {
+	c, err := t.GetByKey(row, key)
+	return c.Ival, err
+}
+
+// GetfByKey returns the float value of the cell at row of the column with the supplied key
+func (t *Table) GetfByKey(row int, key string) (float64, error) {
+	c, err := t.GetByKey(row, key)
+	return c.Fval, err
+}
+
+// GetsByKey returns the string value of the cell at row of the column with the supplied key
+func (t *Table) GetsByKey(row int, key string) (string, error) {
+	c, err := t.GetByKey(row, key)
+	return c.Sval, err
+}
+
+// GetdByKey returns the date value of the cell at row of the column with the supplied key
+func (t *Table) GetdByKey(row int, key string) (time.Time, error) {
+	c, err := t.GetByKey(row, key)
+	return c.Dval, err
+}
+
+// putByKey calls put with the index of the column with the supplied key, if
+// row < 0 then row is set to the last row
+func (t *Table) putByKey(row int, key string, put func(row, col int)) error {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return err
+	}
+	if row < 0 {
+		row = len(t.Row) - 1
+	}
+	if err := t.HasValidRow(row); err != nil {
+		return err
+	}
+	put(row, col)
+	return nil
+}
+
+// PutByKey places Cell c at row of the column with the supplied key, if row < 0
+// then row is set to the last row
+func (t *Table) PutByKey(row int, key string, c Cell) error {
+	return t.putByKey(row, key, func(row, col int) { t.Put(row, col, c) })
+}
+
+// PutiByKey works like Puti for the column with the supplied key
+func (t *Table) PutiByKey(row int, key string, v int64) error {
+	return t.putByKey(row, key, func(row, col int) { t.Puti(row, col, v) })
+}
+
+// PutfByKey works like Putf for the column with the supplied key
+func (t *Table) PutfByKey(row int, key string, v float64) error {
+	return t.putByKey(row, key, func(row, col int) { t.Putf(row, col, v) })
+}
+
+// PutsByKey works like Puts for the column with the supplied key
+func (t *Table) PutsByKey(row int, key string, v string) error {
+	return t.putByKey(row, key, func(row, col int) { t.Puts(row, col, v) })
+}
+
+// PutHTMLByKey works like PutHTML for the column with the supplied key
+func (t *Table) PutHTMLByKey(row int, key string, v string) error {
+	return t.putByKey(row, key, func(row, col int) { t.PutHTML(row, col, v) })
+}
+
+// PutdByKey works like Putd for the column with the supplied key
+func (t *Table) PutdByKey(row int, key string, v time.Time) error {
+	return t.putByKey(row, key, func(row, col int) { t.Putd(row, col, v) })
+}
+
+// PutdtByKey works like Putdt for the column with the supplied key
+func (t *Table) PutdtByKey(row int, key string, v time.Time) error {
+	return t.putByKey(row, key, func(row, col int) { t.Putdt(row, col, v) })
+}
+
+// AggregateByKey computes the aggregate agg of all the rows of the column with
+// the supplied key
+func (t *Table) AggregateByKey(key string, agg AggFunc) (Cell, error) {
+	return t.AggregateRowsByKey(key, 0, len(t.Row)-1, agg)
+}
+
+// AggregateRowsByKey computes the aggregate agg of rows from thru to of the
+// column with the supplied key
+func (t *Table) AggregateRowsByKey(key string, from, to int, agg AggFunc) (Cell, error) {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return Cell{}, err
+	}
+	return t.AggregateRows(col, from, to, agg), nil
+}
+
+// AggregateRowsetByKey computes the aggregate agg of the rows in rowset rsid of
+// the column with the supplied key
+func (t *Table) AggregateRowsetByKey(rsid int, key string, agg AggFunc) (Cell, error) {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return Cell{}, err
+	}
+	return t.AggregateRowset(rsid, col, agg), nil
+}
+
+// SumRowsetByKey works like SumRowset for the column with the supplied key
+func (t *Table) SumRowsetByKey(rsid int, key string) (Cell, error) {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return Cell{}, err
+	}
+	if rsid < 0 || rsid >= len(t.RS) {
+		return Cell{}, fmt.Errorf("Invalid rowset: %d", rsid)
+	}
+	return t.SumRowset(rsid, col), nil
+}
+
+// InsertSumRowByKey works like InsertSumRow for the columns with the supplied keys
+func (t *Table) InsertSumRowByKey(row, from, to int, keys []string) error {
+	cols, err := t.ColumnIndexes(keys...)
+	if err != nil {
+		return err
+	}
+	t.InsertSumRow(row, from, to, cols)
+	return nil
+}
+
+// InsertSumRowsetColsByKey works like InsertSumRowsetCols for the columns with
+// the supplied keys
+func (t *Table) InsertSumRowsetColsByKey(rsid, row int, keys []string) error {
+	cols, err := t.ColumnIndexes(keys...)
+	if err != nil {
+		return err
+	}
+	if rsid < 0 || rsid >= len(t.RS) {
+		return fmt.Errorf("Invalid rowset: %d", rsid)
+	}
+	t.InsertSumRowsetCols(rsid, row, cols)
+	return nil
+}
+
+// SortByKey works like Sort for the column with the supplied key. Use SortBy
+// with SortKey.Key to sort by several columns.
+func (t *Table) SortByKey(from, to int, key string) error {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return err
+	}
+	t.Sort(from, to, col)
+	return nil
+}
+
+// SetHeaderCellCSSByKey works like SetHeaderCellCSS for the column with the supplied key
+func (t *Table) SetHeaderCellCSSByKey(key string, cssList []*CSSProperty) error {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return err
+	}
+	return t.SetHeaderCellCSS(col, cssList)
+}
+
+// SetColCSSByKey works like SetColCSS for the column with the supplied key
+func (t *Table) SetColCSSByKey(key string, cssList []*CSSProperty) error {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return err
+	}
+	return t.SetColCSS(col, cssList)
+}
+
+// SetCellCSSByKey works like SetCellCSS for the column with the supplied key
+func (t *Table) SetCellCSSByKey(row int, key string, cssList []*CSSProperty) error {
+	col, err := t.ColumnIndex(key)
+	if err != nil {
+		return err
+	}
+	return t.SetCellCSS(row, col, cssList)
+}
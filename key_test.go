@@ -0,0 +1,92 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestColumnKey(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumnWithKey("name", "Name", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumnWithKey("amount", "Amount", 8, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Note", 8, CELLSTRING, COLJUSTIFYLEFT)
+	if err := tbl.AddColumnWithKey("name", "Other", 8, CELLSTRING, COLJUSTIFYLEFT); err == nil || tbl.ColCount() != 3 {
+		t.Errorf("key_test: Expected error for duplicate key\n")
+	}
+	if err := tbl.SetColumnKey(2, "note"); err != nil {
+		t.Errorf("key_test: Error setting key: %s\n", err.Error())
+	}
+	for i, name := range []string{"b", "a", "c"} {
+		tbl.AddRow()
+		if err := tbl.PutsByKey(-1, "name", name); err != nil {
+			t.Errorf("key_test: Error putting string: %s\n", err.Error())
+		}
+		tbl.PutiByKey(-1, "amount", int64(i+1))
+	}
+
+	// keys stay with their columns
+	tbl.MoveColumn(0, 2)
+	if col, err := tbl.ColumnIndex("name"); err != nil || col != 2 {
+		t.Errorf("key_test: Expected name to be column 2, found %d\n", col)
+	}
+	if v, err := tbl.GetsByKey(1, "name"); err != nil || v != "a" {
+		t.Errorf("key_test: Expected a, found %q\n", v)
+	}
+	if c, err := tbl.AggregateByKey("amount", AggSum); err != nil || c.Ival != 6 {
+		t.Errorf("key_test: Expected sum 6, found %#v\n", c)
+	}
+	if err := tbl.SortBy(0, 2, SortKey{Key: "name", Descending: true}); err != nil || tbl.Geti(0, 0) != 3 {
+		t.Errorf("key_test: Expected c first, found %v\n", tbl.Row[0])
+	}
+	if err := tbl.SortByKey(0, 2, "name"); err != nil || tbl.Geti(0, 0) != 2 {
+		t.Errorf("key_test: Expected a first, found %v\n", tbl.Row[0])
+	}
+	rs := tbl.CreateRowset()
+	tbl.AppendToRowset(rs, 0)
+	tbl.AppendToRowset(rs, 2)
+	if c, err := tbl.SumRowsetByKey(rs, "amount"); err != nil || c.Ival != 5 {
+		t.Errorf("key_test: Expected rowset sum 5, found %#v\n", c)
+	}
+	if err := tbl.InsertSumRowByKey(3, 0, 2, []string{"amount"}); err != nil || tbl.Geti(3, 0) != 6 {
+		t.Errorf("key_test: Expected sum row with 6, found %v\n", tbl.Row)
+	}
+	if err := tbl.SetColCSSByKey("amount", []*CSSProperty{{Name: "color", Value: "red"}}); err != nil {
+		t.Errorf("key_test: Error setting css: %s\n", err.Error())
+	}
+	if _, ok := tbl.CSS["col-0"]; !ok {
+		t.Errorf("key_test: Expected css of column 0, found %v\n", tbl.CSS)
+	}
+
+	// unknown keys and rows are errors
+	for _, err := range []error{
+		tbl.PutsByKey(0, "nope", "x"),
+		tbl.PutsByKey(9, "name", "x"),
+		tbl.SetCellCSSByKey(0, "nope", nil),
+		tbl.SortBy(0, 1, SortKey{Key: "nope"}),
+		tbl.InsertSumRowsetColsByKey(rs+1, 0, []string{"amount"}),
+		tbl.SetColumnKey(0, "name"),
+	} {
+		if err == nil {
+			t.Errorf("key_test: Expected error\n")
+		}
+	}
+	if _, err := tbl.GetByKey(0, "nope"); err == nil || !strings.Contains(err.Error(), "Unknown column key: nope") {
+		t.Errorf("key_test: Expected unknown key error, found %v\n", err)
+	}
+
+	// keys survive json round-trip
+	var temp bytes.Buffer
+	if err := json.NewEncoder(&temp).Encode(tbl); err != nil {
+		t.Fatalf("key_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(temp.Bytes(), &tbl2); err != nil {
+		t.Fatalf("key_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if col, err := tbl2.ColumnIndex("note"); err != nil || col != 1 {
+		t.Errorf("key_test: Expected key to be preserved, found %d\n", col)
+	}
+}
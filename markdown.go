@@ -0,0 +1,201 @@
+package gotable
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownTable struct used to prepare table in markdown (GitHub-flavored) version
+type MarkdownTable struct {
+	*Table
+	outbuf bytes.Buffer
+}
+
+func (mt *MarkdownTable) writeTableOutput(w io.Writer) error {
+	var tout string
+
+	// append title
+	tout += mt.getTitle()
+
+	// append section 1
+	tout += mt.getSection1()
+
+	// append section 2
+	tout += mt.getSection2()
+
+	// append section 3
+	tout += mt.getSection3()
+
+	var tableOut string
+
+	// append headers
+	if headerStr, err := mt.getHeaders(); err != nil {
+		tableOut += stringln(err.Error())
+	} else {
+
+		// append rows
+		if rowsStr, err := mt.getRows(); err != nil {
+			tableOut += stringln(err.Error())
+			// footer, e.g. a count of 0, is shown under the headers without rows
+			if len(mt.Table.Footer) > 0 {
+				tableOut += NEWLINE + headerStr + mt.getFooter()
+			}
+		} else {
+			// if rows exist, then only show headers
+			tableOut += headerStr
+			tableOut += rowsStr
+		}
+	}
+
+	if tableOut != "" {
+		tout += tableOut
+	}
+
+	// return output
+	if _, err := mt.outbuf.WriteString(tout); err != nil {
+		return err
+	}
+	// write output to passed io.Writer interface object
+	_, err := w.Write(mt.outbuf.Bytes())
+	return err
+}
+
+// markdown blocks must be separated by a blank line, otherwise
+// paragraphs and the table get merged together
+func (mt *MarkdownTable) getBlock(prefix, s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	return stringln(prefix+s) + NEWLINE
+}
+
+func (mt *MarkdownTable) getTitle() string {
+	return mt.getBlock("# ", mt.Table.GetTitle())
+}
+
+func (mt *MarkdownTable) getSection1() string {
+	return mt.getBlock("## ", mt.Table.GetSection1())
+}
+
+func (mt *MarkdownTable) getSection2() string {
+	return mt.getBlock("", mt.Table.GetSection2())
+}
+
+func (mt *MarkdownTable) getSection3() string {
+	return mt.getBlock("", mt.Table.GetSection3())
+}
+
+func (mt *MarkdownTable) getHeaders() (string, error) {
+	// check for blank headers
+	blankHdrsErr := mt.Table.HasHeaders()
+	if blankHdrsErr != nil {
+		return "", blankHdrsErr
+	}
+
+	// format headers and alignment markers
+	var tHeader, tAlign []string
+
+	for i := 0; i < len(mt.Table.ColDefs); i++ {
+		tHeader = append(tHeader, escapeMarkdownCell(mt.Table.ColDefs[i].ColTitle))
+
+		switch mt.Table.ColDefs[i].Justify {
+		case COLJUSTIFYLEFT:
+			tAlign = append(tAlign, ":---")
+		case COLJUSTIFYRIGHT:
+			tAlign = append(tAlign, "---:")
+		default:
+			tAlign = append(tAlign, "---")
+		}
+	}
+
+	s := stringln("| " + strings.Join(tHeader, " | ") + " |")
+	s += stringln("| " + strings.Join(tAlign, " | ") + " |")
+	return s, nil
+}
+
+func (mt *MarkdownTable) getRows() (string, error) {
+	// check for empty data table
+	blankDataErr := mt.Table.HasData()
+	if blankDataErr != nil {
+		return "", blankDataErr
+	}
+
+	var rowsStr string
+	for i := 0; i < mt.Table.RowCount(); i++ {
+		// for valid row, we will never get an error
+		s, _ := mt.getRow(i)
+		rowsStr += s
+	}
+
+	return rowsStr + mt.getFooter(), nil
+}
+
+// getFooter returns the footer rows, the final rows, in bold like the rows
+// after a line
+func (mt *MarkdownTable) getFooter() string {
+	var s string
+	for i := 0; i < len(mt.Table.Footer); i++ {
+		s += mt.getColset(-1, mt.Table.getFooterRow(i), true)
+	}
+	return s
+}
+
+func (mt *MarkdownTable) getRow(row int) (string, error) {
+
+	// markdown tables can't draw horizontal lines, so a row which follows a
+	// separator line (typically a subtotal or total row) is rendered in bold
+	bold := mt.Table.hasLineBefore(row) || (row > 0 && mt.Table.hasLineAfter(row-1))
+	return mt.getColset(row, mt.Table.Row[row], bold), nil
+}
+
+// getColset formats the cells of a row, row is the index of the row in the
+// table, it is used to find merged cells
+func (mt *MarkdownTable) getColset(row int, cs Colset, bold bool) string {
+	// format table row
+	var tRow []string
+
+	for i := 0; i < len(cs.Col); i++ {
+		var s string
+
+		// markdown has no merged cells, the cells hidden by a merge are blank
+		if mt.Table.isMergeCovered(row, i) {
+			tRow = append(tRow, s)
+			continue
+		}
+
+		switch cs.Col[i].Type {
+		case CELLFLOAT:
+			s = mt.Table.ColDefs[i].floatText(cs.Col[i].Fval)
+		case CELLINT:
+			s = fmt.Sprintf(mt.Table.ColDefs[i].Pfmt, cs.Col[i].Ival)
+		case CELLSTRING:
+			// FOR MARKDOWN, APPEND FULL STRING, THERE ARE NO MULTILINE STRING IN THIS
+			s = escapeMarkdownCell(cs.Col[i].Sval)
+		case CELLDATE:
+			s = cs.Col[i].Dval.Format(mt.Table.DateFmt)
+		case CELLDATETIME:
+			s = cs.Col[i].Dval.Format(mt.Table.DateTimeFmt)
+		}
+
+		// padding is meaningless in markdown, alignment markers take care of it
+		s = strings.TrimSpace(s)
+		if bold && s != "" {
+			s = "**" + s + "**"
+		}
+		tRow = append(tRow, s)
+	}
+
+	// append newline char at last
+	return stringln("| " + strings.Join(tRow, " | ") + " |")
+}
+
+// escapeMarkdownCell makes a string safe to be placed inside a pipe table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "|", `\|`, -1)
+	s = strings.Replace(s, "\r\n", "<br>", -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
@@ -0,0 +1,43 @@
+package gotable
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Markdown Table")
+	tbl.SetSection1("Section One")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "pipe | inside")
+	tbl.Putf(-1, 1, 1234.5)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "second")
+	tbl.Putf(-1, 1, 10)
+	tbl.AddLineAfter(tbl.RowCount() - 1)
+	tbl.InsertSumRow(-1, 0, tbl.RowCount()-1, []int{1})
+
+	var temp bytes.Buffer
+	if err := tbl.MarkdownprintTable(&temp); err != nil {
+		t.Errorf("markdown_test: Error creating MARKDOWN output: %s\n", err.Error())
+	}
+	s := temp.String()
+
+	var expected = []string{
+		"# Markdown Table\n\n## Section One\n\n",
+		"| Name | Amount |\n| :--- | ---: |\n",
+		`| pipe \| inside | 1,234.50 |`,
+		"|  | **1,244.50** |",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(s, expected[i]) {
+			t.Errorf("markdown_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+}
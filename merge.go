@@ -0,0 +1,134 @@
+package gotable
+
+import "fmt"
+
+// CellMerge describes a rectangle of cells rendered as one cell. The value and
+// css of the merged cell are those of its top left cell, Row and Col.
+type CellMerge struct {
+	Row     int `json:"row"`     // row of the top left cell
+	Col     int `json:"col"`     // column of the top left cell
+	RowSpan int `json:"rowSpan"` // number of rows covered by the merge
+	ColSpan int `json:"colSpan"` // number of columns covered by the merge
+}
+
+// contains reports whether the cell at row, col is covered by the merge
+func (m CellMerge) contains(row, col int) bool {
+	return row >= m.Row && row < m.Row+m.RowSpan && col >= m.Col && col < m.Col+m.ColSpan
+}
+
+// overlaps reports whether two merges cover a common cell
+func (m CellMerge) overlaps(o CellMerge) bool {
+	return m.Row < o.Row+o.RowSpan && o.Row < m.Row+m.RowSpan &&
+		m.Col < o.Col+o.ColSpan && o.Col < m.Col+m.ColSpan
+}
+
+// MergeCells merges rowSpan rows and colSpan columns starting at row, col into
+// one cell. The merged cell shows the value of the top left cell, the values of
+// the other cells are kept but not shown, except in csv output which can repeat
+// the value (see SetCSVMergeRepeat). Merges must not overlap.
+func (t *Table) MergeCells(row, col, rowSpan, colSpan int) error {
+	if rowSpan < 1 || colSpan < 1 {
+		return fmt.Errorf("Invalid span, rowSpan: %d, colSpan: %d", rowSpan, colSpan)
+	}
+	if rowSpan == 1 && colSpan == 1 {
+		return fmt.Errorf("Merge must cover more than one cell, row: %d, column: %d", row, col)
+	}
+	for _, r := range []int{row, row + rowSpan - 1} {
+		if err := t.HasValidRow(r); err != nil {
+			return err
+		}
+	}
+	for _, c := range []int{col, col + colSpan - 1} {
+		if err := t.HasValidColumn(c); err != nil {
+			return err
+		}
+	}
+
+	m := CellMerge{Row: row, Col: col, RowSpan: rowSpan, ColSpan: colSpan}
+	for i := 0; i < len(t.Merges); i++ {
+		if m.overlaps(t.Merges[i]) {
+			return fmt.Errorf("Merge overlaps existing merge at row: %d, column: %d", t.Merges[i].Row, t.Merges[i].Col)
+		}
+	}
+	t.Merges = append(t.Merges, m)
+	return nil
+}
+
+// UnmergeCells removes the merge whose top left cell is at row, col. It
+// returns false if there is no such merge.
+func (t *Table) UnmergeCells(row, col int) bool {
+	for i := 0; i < len(t.Merges); i++ {
+		if t.Merges[i].Row == row && t.Merges[i].Col == col {
+			t.Merges = append(t.Merges[:i], t.Merges[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// getMerge returns the merge covering the cell at row, col, nil if the cell
+// is not merged
+func (t *Table) getMerge(row, col int) *CellMerge {
+	for i := 0; i < len(t.Merges); i++ {
+		if t.Merges[i].contains(row, col) {
+			return &t.Merges[i]
+		}
+	}
+	return nil
+}
+
+// isMergeCovered reports whether the cell at row, col is hidden by a merge,
+// i.e. it is merged but it is not the top left cell
+func (t *Table) isMergeCovered(row, col int) bool {
+	m := t.getMerge(row, col)
+	return m != nil && (m.Row != row || m.Col != col)
+}
+
+// isRowSpanned reports whether a merge spans from the previous row into row,
+// such rows can not be separated, e.g. in groups of interactive html output
+func (t *Table) isRowSpanned(row int) bool {
+	for i := 0; i < len(t.Merges); i++ {
+		if t.Merges[i].Row < row && row < t.Merges[i].Row+t.Merges[i].RowSpan {
+			return true
+		}
+	}
+	return false
+}
+
+// adjustMergesInsertRow keeps merges consistent after a row is inserted at
+// index row. A row inserted inside a merge extends the merge.
+func (t *Table) adjustMergesInsertRow(row int) {
+	for i := 0; i < len(t.Merges); i++ {
+		m := &t.Merges[i]
+		if m.Row >= row {
+			m.Row++
+		} else if row < m.Row+m.RowSpan {
+			m.RowSpan++
+		}
+	}
+}
+
+// adjustMergesDeleteRow keeps merges consistent before the row at index row is
+// deleted. If the top row of a merge is deleted, its value moves to the next
+// row. Merges which are reduced to a single cell are removed.
+func (t *Table) adjustMergesDeleteRow(row int) {
+	var merges []CellMerge
+	for i := 0; i < len(t.Merges); i++ {
+		m := t.Merges[i]
+		switch {
+		case m.Row > row:
+			m.Row--
+		case m.Row == row:
+			if m.RowSpan > 1 {
+				t.Row[row+1].Col[m.Col] = t.Row[row].Col[m.Col]
+			}
+			m.RowSpan--
+		case row < m.Row+m.RowSpan:
+			m.RowSpan--
+		}
+		if m.RowSpan > 0 && m.RowSpan*m.ColSpan > 1 {
+			merges = append(merges, m)
+		}
+	}
+	t.Merges = merges
+}
@@ -0,0 +1,188 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func getMergeTable() *Table {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Merge Table")
+	tbl.AddColumn("Category", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Name", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	for i, name := range []string{"apple", "pear"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "fruit")
+		tbl.Puts(-1, 1, name)
+		tbl.Puti(-1, 2, int64(i+1))
+	}
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Fruit total")
+	tbl.Puti(-1, 2, 3)
+	return &tbl
+}
+
+func TestMergeCells(t *testing.T) {
+	tbl := getMergeTable()
+	if err := tbl.MergeCells(0, 0, 2, 1); err != nil {
+		t.Fatalf("merge_test: Error merging cells: %s\n", err.Error())
+	}
+	if err := tbl.MergeCells(2, 0, 1, 2); err != nil {
+		t.Fatalf("merge_test: Error merging cells: %s\n", err.Error())
+	}
+
+	// invalid merges
+	var invalid = [][4]int{
+		{0, 0, 1, 1}, // single cell
+		{0, 2, 0, 1}, // zero span
+		{2, 1, 2, 1}, // beyond last row
+		{0, 2, 1, 2}, // beyond last column
+		{1, 0, 1, 2}, // overlaps
+	}
+	for _, m := range invalid {
+		if err := tbl.MergeCells(m[0], m[1], m[2], m[3]); err == nil {
+			t.Errorf("merge_test: Expected error for merge %v\n", m)
+		}
+	}
+
+	// text: merged cell takes the width of its columns
+	var temp bytes.Buffer
+	if err := tbl.TextprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating TEXT output: %s\n", err.Error())
+	}
+	expected := "fruit     apple       1\n" +
+		"          pear        2\n" +
+		"Fruit total           3\n"
+	if !strings.HasSuffix(temp.String(), expected) {
+		t.Errorf("merge_test: Expected %q at the end of text output, but found:\n%s\n", expected, temp.String())
+	}
+
+	// html: colspan and rowspan, hidden cells are not rendered
+	temp.Reset()
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := strings.Join(strings.Fields(temp.String()), " ")
+	for _, e := range []string{
+		`<tr> <td class="col-0" rowspan="2"> fruit </td> <td class="col-1"> apple </td>`,
+		`<tr> <td class="col-1"> pear </td> <td class="col-2"> 2 </td> </tr>`,
+		`<tr> <td class="col-0" colspan="2"> Fruit total </td> <td class="col-2"> 3 </td> </tr>`,
+	} {
+		if !strings.Contains(s, e) {
+			t.Errorf("merge_test: Expected %q in html output, but found:\n%s\n", e, temp.String())
+		}
+	}
+
+	// csv: hidden cells are blank, or repeat the value
+	temp.Reset()
+	if err := tbl.CSVprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating CSV output: %s\n", err.Error())
+	}
+	if !strings.Contains(temp.String(), `"","pear",    2`) {
+		t.Errorf("merge_test: Expected blank merged cell in csv output, but found:\n%s\n", temp.String())
+	}
+	tbl.SetCSVMergeRepeat(true)
+	temp.Reset()
+	if err := tbl.CSVprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating CSV output: %s\n", err.Error())
+	}
+	for _, e := range []string{`"fruit","pear",    2`, `"Fruit total","Fruit total",    3`} {
+		if !strings.Contains(temp.String(), e) {
+			t.Errorf("merge_test: Expected %q in csv output, but found:\n%s\n", e, temp.String())
+		}
+	}
+
+	// xlsx: merged ranges below title and header rows
+	temp.Reset()
+	if err := tbl.XLSXprintTable(&temp); err != nil {
+		t.Fatalf("merge_test: Error creating XLSX output: %s\n", err.Error())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(temp.Bytes()), int64(temp.Len()))
+	if err != nil {
+		t.Fatalf("merge_test: Error reading XLSX output: %s\n", err.Error())
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			b, _ := ioutil.ReadAll(r)
+			sheet = string(b)
+		}
+	}
+	for _, e := range []string{`<mergeCell ref="A3:A4"/>`, `<mergeCell ref="A5:B5"/>`} {
+		if !strings.Contains(sheet, e) {
+			t.Errorf("merge_test: Expected %q in xlsx sheet, but found:\n%s\n", e, sheet)
+		}
+	}
+
+	// markdown: hidden cells are blank
+	temp.Reset()
+	if err := tbl.MarkdownprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating MARKDOWN output: %s\n", err.Error())
+	}
+	if !strings.Contains(temp.String(), "|  | pear | 2 |") {
+		t.Errorf("merge_test: Expected blank merged cell in markdown output, but found:\n%s\n", temp.String())
+	}
+
+	// pdf: only checks that merged rows are rendered
+	temp.Reset()
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Errorf("merge_test: Error creating PDF output: %s\n", err.Error())
+	}
+
+	// merges survive json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("merge_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("merge_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if len(tbl2.Merges) != 2 || tbl2.Merges[1] != (CellMerge{Row: 2, Col: 0, RowSpan: 1, ColSpan: 2}) || !tbl2.csvMergeRepeat {
+		t.Errorf("merge_test: Expected merges to be preserved, found %v\n", tbl2.Merges)
+	}
+
+	if !tbl.UnmergeCells(2, 0) || tbl.UnmergeCells(2, 0) || len(tbl.Merges) != 1 {
+		t.Errorf("merge_test: Expected merge to be removed once, found %v\n", tbl.Merges)
+	}
+}
+
+func TestMergeInsertDeleteRow(t *testing.T) {
+	tbl := getMergeTable()
+	tbl.MergeCells(0, 0, 2, 1)
+	tbl.MergeCells(2, 0, 1, 2)
+
+	// row inserted inside a merge extends it, the merges below move down
+	tbl.InsertRow(1)
+	expected := []CellMerge{{Row: 0, Col: 0, RowSpan: 3, ColSpan: 1}, {Row: 3, Col: 0, RowSpan: 1, ColSpan: 2}}
+	for i := 0; i < len(expected); i++ {
+		if tbl.Merges[i] != expected[i] {
+			t.Errorf("merge_test: After insert, expected merge %v, found %v\n", expected[i], tbl.Merges[i])
+		}
+	}
+
+	// deleting top row of a merge moves the value to the next row
+	tbl.DeleteRow(0)
+	expected = []CellMerge{{Row: 0, Col: 0, RowSpan: 2, ColSpan: 1}, {Row: 2, Col: 0, RowSpan: 1, ColSpan: 2}}
+	for i := 0; i < len(expected); i++ {
+		if tbl.Merges[i] != expected[i] {
+			t.Errorf("merge_test: After delete, expected merge %v, found %v\n", expected[i], tbl.Merges[i])
+		}
+	}
+	if tbl.Gets(0, 0) != "fruit" {
+		t.Errorf("merge_test: Expected merged value in row 0, found %q\n", tbl.Gets(0, 0))
+	}
+
+	// merge reduced to a single cell is removed
+	tbl.DeleteRow(1)
+	if len(tbl.Merges) != 1 || tbl.Merges[0] != (CellMerge{Row: 1, Col: 0, RowSpan: 1, ColSpan: 2}) {
+		t.Errorf("merge_test: Expected single merge left, found %v\n", tbl.Merges)
+	}
+}
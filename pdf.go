@@ -2,29 +2,184 @@ package gotable
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
 	"os/exec"
-	"path"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // WKHTMLTOPDFCMD command : html > pdf
+// DATETIMEFMT is the default layout of the timestamp in the pdf footer, the
+// zone is the abbreviation of the local time zone, not the literal "IST" of
+// earlier versions
 const (
 	WKHTMLTOPDFCMD = "wkhtmltopdf"
-	TEMPSTORE      = "."
-	DATETIMEFMT    = "_2 Jan 2006 3:04 PM IST"
+	DATETIMEFMT    = "_2 Jan 2006 3:04 PM MST"
 )
 
+// TEMPSTORE was the directory of the temporary html file converted by wkhtmltopdf.
+//
+// Deprecated: html is fed to wkhtmltopdf through stdin, TEMPSTORE is not used.
+const TEMPSTORE = "."
+
+// PDFENGINENATIVE et. al. are the constants used to select the pdf engine
+const (
+	PDFENGINENATIVE      = 1 // pure go renderer, default
+	PDFENGINEWKHTMLTOPDF = 2 // html output converted by external wkhtmltopdf binary
+)
+
+// PDFPAGELETTER et. al. are the page sizes and orientations supported in PDFOptions
+const (
+	PDFPAGELETTER  = "Letter"
+	PDFPAGELEGAL   = "Legal"
+	PDFPAGETABLOID = "Tabloid"
+	PDFPAGEA3      = "A3"
+	PDFPAGEA4      = "A4"
+	PDFPAGEA5      = "A5"
+
+	PDFLANDSCAPE = "Landscape"
+	PDFPORTRAIT  = "Portrait"
+)
+
+// PDFPAGETITLE et. al. are the placeholders which can be used in header
+// and footer texts of PDFOptions
+const (
+	PDFPAGETITLE     = "[title]"
+	PDFPAGETIMESTAMP = "[timestamp]"
+	PDFPAGENUMBER    = "[page]"
+	PDFPAGECOUNT     = "[toPage]"
+)
+
+// pdfPageSizes holds portrait page sizes in points
+var pdfPageSizes = map[string][2]float64{
+	PDFPAGELETTER:  {612, 792},
+	PDFPAGELEGAL:   {612, 1008},
+	PDFPAGETABLOID: {792, 1224},
+	PDFPAGEA3:      {841.89, 1190.55},
+	PDFPAGEA4:      {595.28, 841.89},
+	PDFPAGEA5:      {419.53, 595.28},
+}
+
+// PDFOptions holds page setup, header/footer and metadata options of pdf output.
+// Get the defaults from DefaultPDFOptions and override what you need.
+type PDFOptions struct {
+	Engine      int    // PDFENGINENATIVE or PDFENGINEWKHTMLTOPDF
+	PageSize    string // PDFPAGELETTER, PDFPAGEA4, ...
+	Orientation string // PDFLANDSCAPE or PDFPORTRAIT
+
+	// page margins in millimeters
+	MarginTop, MarginBottom, MarginLeft, MarginRight float64
+
+	// header and footer texts, they can contain PDFPAGETITLE, PDFPAGETIMESTAMP,
+	// PDFPAGENUMBER and PDFPAGECOUNT placeholders
+	HeaderLeft, HeaderCenter, HeaderRight string
+	FooterLeft, FooterCenter, FooterRight string
+
+	// font of header and footer texts, the native engine supports the standard
+	// Helvetica and Courier families only and uses Helvetica for anything else
+	FontName string
+	FontSize float64
+
+	// timestamp used for PDFPAGETIMESTAMP placeholder
+	NoTimestamp       bool           // if true, placeholder is replaced with blank
+	TimestampFormat   string         // time layout, DATETIMEFMT by default
+	TimestampLocation *time.Location // time zone, local time zone by default
+
+	// document metadata, Title defaults to table title. wkhtmltopdf can't
+	// set Author and Subject, they are supported by the native engine only
+	Title, Author, Subject string
+}
+
+// DefaultPDFOptions returns the options used by PDFprintTable
+func DefaultPDFOptions() PDFOptions {
+	return PDFOptions{
+		Engine:          PDFENGINENATIVE,
+		PageSize:        PDFPAGELETTER,
+		Orientation:     PDFLANDSCAPE,
+		MarginTop:       15,
+		MarginBottom:    15,
+		MarginLeft:      10,
+		MarginRight:     10,
+		HeaderCenter:    PDFPAGETITLE,
+		FooterLeft:      PDFPAGETIMESTAMP,
+		FooterRight:     "Page " + PDFPAGENUMBER + " of " + PDFPAGECOUNT,
+		FontSize:        7,
+		TimestampFormat: DATETIMEFMT,
+	}
+}
+
 // PDFTable struct used to prepare table in pdf version
 type PDFTable struct {
 	*Table
-	outbuf bytes.Buffer
+	Options PDFOptions
+	ctx     context.Context
+	outbuf  bytes.Buffer
 }
 
 func (pt *PDFTable) writeTableOutput(w io.Writer) error {
+	if pt.Options.Engine == PDFENGINEWKHTMLTOPDF {
+		return pt.writeWkhtmltopdfOutput(w)
+	}
+	return pt.writeNativeOutput(w)
+}
+
+// getContext returns the context of pdf generation
+func (pt *PDFTable) getContext() context.Context {
+	if pt.ctx == nil {
+		return context.Background()
+	}
+	return pt.ctx
+}
+
+// getPageSize returns width and height of the page in points
+func (pt *PDFTable) getPageSize() (float64, float64) {
+	size, ok := pdfPageSizes[pt.Options.PageSize]
+	if !ok {
+		size = pdfPageSizes[PDFPAGELETTER]
+	}
+	if pt.Options.Orientation == PDFPORTRAIT {
+		return size[0], size[1]
+	}
+	return size[1], size[0]
+}
+
+// getTitle returns the document title
+func (pt *PDFTable) getTitle() string {
+	if pt.Options.Title != "" {
+		return pt.Options.Title
+	}
+	return strings.TrimSpace(pt.Table.GetTitle())
+}
+
+// getTimestamp returns the export time formatted as per options
+func (pt *PDFTable) getTimestamp() string {
+	if pt.Options.NoTimestamp {
+		return ""
+	}
+	loc := pt.Options.TimestampLocation
+	if loc == nil {
+		loc = time.Local
+	}
+	f := pt.Options.TimestampFormat
+	if f == "" {
+		f = DATETIMEFMT
+	}
+	return time.Now().In(loc).Format(f)
+}
+
+// replacePlaceholders replaces the title and timestamp placeholders in s,
+// page placeholders are left to the engine
+func (pt *PDFTable) replacePlaceholders(s, timestamp string) string {
+	return strings.NewReplacer(PDFPAGETITLE, pt.getTitle(), PDFPAGETIMESTAMP, timestamp).Replace(s)
+}
+
+// writeWkhtmltopdfOutput converts html output of the table with wkhtmltopdf.
+// The html is fed through stdin and the pdf is streamed from stdout straight
+// to w, so nothing is written to the filesystem
+func (pt *PDFTable) writeWkhtmltopdfOutput(w io.Writer) error {
 
 	// get html output first
 	var temp bytes.Buffer
@@ -32,6 +187,7 @@ func (pt *PDFTable) writeTableOutput(w io.Writer) error {
 	// copy table object so that we can override properties over table
 	// so it won't affect original table
 	var pdfTable = *pt.Table
+	pdfTable.htmlInteractive = nil
 	var ht = &HTMLTable{Table: &pdfTable}
 
 	// set custom values over ht
@@ -43,105 +199,95 @@ func (pt *PDFTable) writeTableOutput(w io.Writer) error {
 		return err
 	}
 
-	htmlString := temp.String()
-
-	timeCharReplacer := strings.NewReplacer(":", "-", ".", "", "T", "")
-	currentTime := timeCharReplacer.Replace(time.Now().Format(time.RFC3339Nano))
+	// prepare command, child process is killed if the context is done
+	// before it exits
+	wkhtmltopdf := exec.CommandContext(pt.getContext(), WKHTMLTOPDFCMD, pt.getWkhtmltopdfArgs()...)
 
-	// create temp file
-	filePath := path.Join(TEMPSTORE, "tablePDF_"+currentTime)
+	var stderr bytes.Buffer
+	wkhtmltopdf.Stdin = &temp
+	wkhtmltopdf.Stdout = w
+	wkhtmltopdf.Stderr = &stderr
 
-	// only works with html file extension
-	// be careful, must append it
-	tempHTMLFile, err := os.Create(filePath + ".html")
-	if err != nil {
-		return err
-	}
-	// write html string to file
-	_, err = tempHTMLFile.WriteString(htmlString)
-	if err != nil {
-		return err
+	if err := wkhtmltopdf.Run(); err != nil {
+		if ctxErr := pt.getContext().Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s: %s", WKHTMLTOPDFCMD, err.Error(), msg)
+		}
+		return fmt.Errorf("%s: %s", WKHTMLTOPDFCMD, err.Error())
 	}
-	tempHTMLFile.Close()
 
-	// remove this temp file after operation
-	defer os.Remove(tempHTMLFile.Name())
-
-	// return output file path
-	if err = pt.writePDFBuffer(filePath); err != nil {
-		return err
-	}
-
-	// write output to passed io.Writer interface object
-	_, err = w.Write(pt.outbuf.Bytes())
-	return err
+	return nil
 }
 
-func (pt *PDFTable) writePDFBuffer(inputFile string) error {
+// getWkhtmltopdfArgs returns the command line arguments of wkhtmltopdf
+// which reads html from stdin and writes pdf to stdout
+func (pt *PDFTable) getWkhtmltopdfArgs() []string {
 
-	pdfExportTime := time.Now().Format(DATETIMEFMT)
-	htmlExportFile := inputFile + ".html"
+	pdfExportTime := pt.getTimestamp()
+	mm := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) + "mm" }
+
+	pageSize := pt.Options.PageSize
+	if pageSize == "" {
+		pageSize = PDFPAGELETTER
+	}
+	orientation := pt.Options.Orientation
+	if orientation == "" {
+		orientation = PDFLANDSCAPE
+	}
+	fontName := pt.Options.FontName
+	if fontName == "" {
+		fontName = "opensans"
+	}
+	fontSize := strconv.FormatFloat(pt.Options.FontSize, 'f', -1, 64)
+	if pt.Options.FontSize <= 0 {
+		fontSize = "7"
+	}
 
 	cmdArgs := []string{
-		// top margin
-		"-T", "15",
-		// header center content
-		"--header-center", pt.Table.GetTitle(),
+		// don't print progress on stderr, keep it for errors only
+		"--quiet",
+		// margins
+		"-T", mm(pt.Options.MarginTop),
+		"-B", mm(pt.Options.MarginBottom),
+		"-L", mm(pt.Options.MarginLeft),
+		"-R", mm(pt.Options.MarginRight),
 		// header font size
-		"--header-font-size", "7",
+		"--header-font-size", fontSize,
 		// header font
-		"--header-font-name", "opensans",
+		"--header-font-name", fontName,
 		// header spacing
 		"--header-spacing", "3",
-		// bottom margin
-		"-B", "15",
 		// footer spacing
 		"--footer-spacing", "5",
 		// footer font
-		"--footer-font-name", "opensans",
+		"--footer-font-name", fontName,
 		// footer font size
-		"--footer-font-size", "7",
-		// footer left content
-		"--footer-left", pdfExportTime,
-		// footer right content
-		"--footer-right", "Page [page] of [toPage]",
+		"--footer-font-size", fontSize,
 		// page size
-		"--page-size", "Letter",
+		"--page-size", pageSize,
 		// orientation
-		"--orientation", "Landscape",
-		// input, output
-		htmlExportFile, "-",
+		"--orientation", orientation,
+		// document title
+		"--title", pt.getTitle(),
 	}
 
-	// prepare command
-	wkhtmltopdf := exec.Command(WKHTMLTOPDFCMD, cmdArgs...)
-
-	// REF: https://github.com/aodin/go-pdf-server/blob/master/pdf_server.go
-
-	// get output pipeline
-	output, err := wkhtmltopdf.StdoutPipe()
-	if err != nil {
-		return err
+	// header and footer content, wkhtmltopdf replaces page placeholders itself
+	hf := []struct{ arg, text string }{
+		{"--header-left", pt.Options.HeaderLeft},
+		{"--header-center", pt.Options.HeaderCenter},
+		{"--header-right", pt.Options.HeaderRight},
+		{"--footer-left", pt.Options.FooterLeft},
+		{"--footer-center", pt.Options.FooterCenter},
+		{"--footer-right", pt.Options.FooterRight},
 	}
-
-	// Begin the command
-	if err = wkhtmltopdf.Start(); err != nil {
-		return err
+	for _, x := range hf {
+		if s := pt.replacePlaceholders(x.text, pdfExportTime); s != "" {
+			cmdArgs = append(cmdArgs, x.arg, s)
+		}
 	}
 
-	// Read the generated PDF from std out
-	b, err := ioutil.ReadAll(output)
-	if err != nil {
-		return err
-	}
-
-	// End the command
-	if err = wkhtmltopdf.Wait(); err != nil {
-		return err
-	}
-
-	// write output to buffer
-	pt.outbuf.Write(b)
-
-	return nil
+	// input from stdin, output to stdout
+	return append(cmdArgs, "-", "-")
 }
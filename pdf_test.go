@@ -0,0 +1,161 @@
+package gotable
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFNative(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("PDF (Native) Table")
+	tbl.SetSection1("Section One")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Notes", 30, CELLSTRING, COLJUSTIFYLEFT)
+
+	// enough rows to span several pages
+	for i := 0; i < 120; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "Row "+strconv.Itoa(i))
+		tbl.Putf(-1, 1, float64(i)*1.5)
+		tbl.Puts(-1, 2, "A whole, big, line with lots and lots and lots and lots of notes. And some more notes.")
+	}
+	tbl.Puts(0, 2, "First\tline\r\nSecond line") // line breaks are kept
+	tbl.AddLineAfter(tbl.RowCount() - 1)
+	tbl.InsertSumRow(-1, 0, tbl.RowCount()-1, []int{1})
+
+	var temp bytes.Buffer
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Fatalf("pdf_test: Error creating PDF output: %s\n", err.Error())
+	}
+	out := temp.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("pdf_test: Output is not a pdf document\n")
+	}
+
+	// startxref must point to the cross reference table
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if m == nil {
+		t.Fatalf("pdf_test: startxref not found\n")
+	}
+	if xref, _ := strconv.Atoi(m[1]); !strings.HasPrefix(out[xref:], "xref\n") {
+		t.Errorf("pdf_test: startxref %d does not point to xref table\n", xref)
+	}
+
+	// inflate all the page contents
+	var content string
+	for _, s := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(out, -1) {
+		zr, err := zlib.NewReader(strings.NewReader(s[1]))
+		if err != nil {
+			t.Fatalf("pdf_test: Error inflating page content: %s\n", err.Error())
+		}
+		b, _ := ioutil.ReadAll(zr)
+		content += string(b)
+	}
+
+	pages := strings.Count(out, "/Type /Page ")
+	if pages < 2 {
+		t.Errorf("pdf_test: Expected several pages, found %d\n", pages)
+	}
+	var expected = []string{
+		`(PDF \(Native\) Table) Tj`,
+		"(Page 1 of " + strconv.Itoa(pages) + ") Tj",
+		"(Page " + strconv.Itoa(pages) + " of " + strconv.Itoa(pages) + ") Tj",
+		"(Row 119) Tj",
+		"(10,710.00) Tj",
+		"(Firstline) Tj",
+		"(Second line) Tj",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(content, expected[i]) {
+			t.Errorf("pdf_test: Expected %q in page content\n", expected[i])
+		}
+	}
+	// headers are repeated on each page
+	if n := strings.Count(content, "(Amount) Tj"); n != pages {
+		t.Errorf("pdf_test: Expected headers on %d pages, found %d\n", pages, n)
+	}
+}
+
+func TestPDFOptions(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Options")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "only row")
+
+	opts := DefaultPDFOptions()
+	opts.PageSize = PDFPAGEA4
+	opts.Orientation = PDFPORTRAIT
+	opts.FontName = "Courier"
+	opts.NoTimestamp = true
+	opts.HeaderCenter = ""
+	opts.FooterCenter = PDFPAGETITLE + " - " + PDFPAGENUMBER + "/" + PDFPAGECOUNT
+	opts.Author = "Jane Doe"
+	opts.Subject = "Testing"
+
+	var temp bytes.Buffer
+	if err := tbl.PDFprintTableWithOptions(&temp, opts); err != nil {
+		t.Fatalf("pdf_test: Error creating PDF output: %s\n", err.Error())
+	}
+	out := temp.String()
+
+	var expected = []string{
+		"/MediaBox [0 0 595.28 841.89]",
+		"/BaseFont /Courier ",
+		"/Author (Jane Doe)",
+		"/Subject (Testing)",
+		"/Title (Options)",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(out, expected[i]) {
+			t.Errorf("pdf_test: Expected %q in pdf document\n", expected[i])
+		}
+	}
+
+	m := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindStringSubmatch(out)
+	zr, err := zlib.NewReader(strings.NewReader(m[1]))
+	if err != nil {
+		t.Fatalf("pdf_test: Error inflating page content: %s\n", err.Error())
+	}
+	b, _ := ioutil.ReadAll(zr)
+	if !strings.Contains(string(b), "(Options - 1/1) Tj") {
+		t.Errorf("pdf_test: Expected footer with placeholders replaced, found:\n%s\n", b)
+	}
+	if strings.Count(string(b), "Tj") != 5 { // title, column header, row and two footers
+		t.Errorf("pdf_test: Expected no header or timestamp, found:\n%q\n", b)
+	}
+}
+
+func TestPDFContext(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "only row")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var temp bytes.Buffer
+	if err := tbl.PDFprintTableContext(ctx, &temp); err != context.Canceled {
+		t.Errorf("pdf_test: Expected %v, found %v\n", context.Canceled, err)
+	}
+	if temp.Len() != 0 {
+		t.Errorf("pdf_test: Expected no output for cancelled context, found len = %d\n", temp.Len())
+	}
+
+	opts := DefaultPDFOptions()
+	opts.Engine = PDFENGINEWKHTMLTOPDF
+	if err := tbl.PDFprintTableWithOptionsContext(ctx, &temp, opts); err == nil {
+		t.Errorf("pdf_test: Expected an error for cancelled context\n")
+	}
+}
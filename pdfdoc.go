@@ -0,0 +1,318 @@
+package gotable
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfDoc is a minimal pdf document writer used by the native pdf engine.
+// It supports the standard Helvetica and Courier fonts (no embedding needed),
+// text and lines. Coordinates are in points with origin at the top left corner of
+// the page, they are converted to pdf user space while writing.
+type pdfDoc struct {
+	width, height float64         // page size in points
+	pages         []*bytes.Buffer // content stream of each page
+	cur           *bytes.Buffer   // content stream of current page
+	family        string          // font family, Helvetica or Courier
+	bold          bool            // current font
+	size          float64         // current font size
+	color         pdfColor        // current text color
+	lineColor     pdfColor        // current line color
+	info          [][2]string     // document information dictionary entries
+}
+
+// newPDFDoc returns a new document with the given page size in points
+func newPDFDoc(width, height float64) *pdfDoc {
+	return &pdfDoc{width: width, height: height, family: "Helvetica", size: 10}
+}
+
+// setFamily sets the font family of the document, Helvetica is used for
+// anything other than the standard Helvetica and Courier families
+func (d *pdfDoc) setFamily(family string) {
+	if strings.EqualFold(family, "Courier") {
+		d.family = "Courier"
+	} else {
+		d.family = "Helvetica"
+	}
+}
+
+// addPage starts a new page, all subsequent drawing goes to it
+func (d *pdfDoc) addPage() {
+	d.cur = &bytes.Buffer{}
+	d.pages = append(d.pages, d.cur)
+}
+
+// setPage makes the page at given index, 0 based, the current page
+func (d *pdfDoc) setPage(i int) {
+	d.cur = d.pages[i]
+}
+
+// addInfo adds an entry to the document information dictionary, e.g. Title
+func (d *pdfDoc) addInfo(key, value string) {
+	if value != "" {
+		d.info = append(d.info, [2]string{key, value})
+	}
+}
+
+// setFont sets the font used for text drawn afterwards
+func (d *pdfDoc) setFont(bold bool, size float64) {
+	d.bold = bold
+	d.size = size
+}
+
+// setColor sets the color of text drawn afterwards
+func (d *pdfDoc) setColor(c pdfColor) {
+	d.color = c
+}
+
+// setLineColor sets the color of lines drawn afterwards
+func (d *pdfDoc) setLineColor(c pdfColor) {
+	d.lineColor = c
+}
+
+// text draws s with its baseline at x,y using the current font
+func (d *pdfDoc) text(x, y float64, s string) {
+	font := "F1"
+	if d.bold {
+		font = "F2"
+	}
+	op := fmt.Sprintf("BT /%s %s Tf %s %s Td (%s) Tj ET", font, pdfNum(d.size), pdfNum(x), pdfNum(d.height-y), pdfEscape(pdfWinAnsi(s)))
+	if d.color != (pdfColor{}) {
+		// colors are saved and restored, black is the default
+		op = "q " + d.color.op("rg") + " " + op + " Q"
+	}
+	fmt.Fprintln(d.cur, op)
+}
+
+// line draws a line from x1,y1 to x2,y2 with given line width
+func (d *pdfDoc) line(x1, y1, x2, y2, width float64) {
+	op := fmt.Sprintf("%s w %s %s m %s %s l S", pdfNum(width), pdfNum(x1), pdfNum(d.height-y1), pdfNum(x2), pdfNum(d.height-y2))
+	if d.lineColor != (pdfColor{}) {
+		op = "q " + d.lineColor.op("RG") + " " + op + " Q"
+	}
+	fmt.Fprintln(d.cur, op)
+}
+
+// rect fills a rectangle of size w,h with top left corner at x,y
+func (d *pdfDoc) rect(x, y, w, h float64, c pdfColor) {
+	fmt.Fprintf(d.cur, "q %s %s %s %s %s re f Q\n", c.op("rg"), pdfNum(x), pdfNum(d.height-y-h), pdfNum(w), pdfNum(h))
+}
+
+// op returns the pdf operator which sets the color, rg for fill and RG for stroke
+func (c pdfColor) op(operator string) string {
+	return pdfNum(c[0]) + " " + pdfNum(c[1]) + " " + pdfNum(c[2]) + " " + operator
+}
+
+// stringWidth returns the width of s in points for the current font
+func (d *pdfDoc) stringWidth(s string) float64 {
+	if d.family == "Courier" {
+		// monospaced font
+		return float64(600*len(pdfWinAnsi(s))) * d.size / 1000
+	}
+	widths := &pdfHelveticaWidths
+	if d.bold {
+		widths = &pdfHelveticaBoldWidths
+	}
+	var w int
+	for _, b := range []byte(pdfWinAnsi(s)) {
+		if b >= 32 && b <= 126 {
+			w += widths[b-32]
+		} else if b == 0xA0 {
+			w += widths[0]
+		} else {
+			w += 556 // close enough for accented letters and symbols
+		}
+	}
+	return float64(w) * d.size / 1000
+}
+
+// wrapText breaks s into lines that fit in width points using the current font.
+// Explicit line breaks are kept, words longer than width are broken at character
+// boundary
+func (d *pdfDoc) wrapText(s string, width float64) []string {
+	var lines []string
+	for _, textLine := range strings.Split(s, "\n") {
+		var cur string
+		for _, word := range strings.Split(standardizeSpaces(strings.TrimSuffix(textLine, "\r")), " ") {
+			candidate := word
+			if cur != "" {
+				candidate = cur + " " + word
+			}
+			if d.stringWidth(candidate) <= width {
+				cur = candidate
+				continue
+			}
+			if cur != "" {
+				lines = append(lines, cur)
+			}
+			// break the long word so that each chunk fits in
+			cur = ""
+			for _, r := range word {
+				if cur != "" && d.stringWidth(cur+string(r)) > width {
+					lines = append(lines, cur)
+					cur = ""
+				}
+				cur += string(r)
+			}
+		}
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// write renders the complete pdf document to w
+func (d *pdfDoc) write(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// object numbers of fixed objects, pages follow them
+	const (
+		catalogObj = 1
+		pagesObj   = 2
+		fontObj    = 3
+		boldObj    = 4
+		infoObj    = 5
+		firstPage  = 6
+	)
+
+	addObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, strconv.Itoa(firstPage+2*i)+" 0 R")
+	}
+	addObj(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+	addObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	addObj("<< /Type /Font /Subtype /Type1 /BaseFont /" + d.family + " /Encoding /WinAnsiEncoding >>")
+	addObj("<< /Type /Font /Subtype /Type1 /BaseFont /" + d.family + "-Bold /Encoding /WinAnsiEncoding >>")
+
+	info := "<< /Producer " + pdfTextString("gotable")
+	for _, kv := range d.info {
+		info += " /" + kv[0] + " " + pdfTextString(kv[1])
+	}
+	addObj(info + " >>")
+
+	for i, page := range d.pages {
+		addObj(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, pdfNum(d.width), pdfNum(d.height), fontObj, boldObj, firstPage+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		addObj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String()))
+	}
+
+	// cross reference table
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogObj, infoObj, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfNum formats a number for pdf content stream, 1/100 of a point is
+// precise enough for any output device
+func pdfNum(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// pdfEscape escapes a byte string to be placed in a pdf literal string
+func pdfEscape(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfTextString returns s as a pdf text string, used in document information
+func pdfTextString(s string) string {
+	for _, r := range s {
+		if r > 126 {
+			// UTF-16BE with byte order mark
+			var b bytes.Buffer
+			b.WriteString("<FEFF")
+			for _, u := range utf16.Encode([]rune(s)) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			return b.String() + ">"
+		}
+	}
+	return "(" + pdfEscape(s) + ")"
+}
+
+// pdfWinAnsiSpecials holds the non latin-1 characters of WinAnsiEncoding
+var pdfWinAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfWinAnsi converts s to WinAnsiEncoding used by the standard fonts,
+// characters which can't be encoded are replaced with `?`
+func pdfWinAnsi(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := pdfWinAnsiSpecials[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// character widths of standard fonts for characters 32..126, from the adobe font metrics
+var pdfHelveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var pdfHelveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
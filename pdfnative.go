@@ -0,0 +1,407 @@
+package gotable
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PDFFONTSIZE et. al. are the constants used in native pdf rendering, in points
+const (
+	PDFFONTSIZE    = 8
+	PDFCELLPADDING = 3
+	PDFPTPERMM     = 72 / 25.4
+)
+
+// pdfLayout holds the state of native pdf rendering
+type pdfLayout struct {
+	doc       *pdfDoc
+	left      float64   // left margin
+	right     float64   // right edge of printable area
+	top       float64   // top margin
+	bottom    float64   // content must not go beyond this position
+	colX      []float64 // left position of each column
+	colW      []float64 // width of each column
+	y         float64   // current vertical position
+	lineH     float64   // height of one line of text
+	headerFun func()    // draws column headers on a new page
+	footerFun func()    // draws footer rows at the end of a page
+	footerH   float64   // height of footer rows, reserved on each page
+
+	// look of the table, set from the theme
+	fontSize    float64   // font size of headers and cells
+	color       pdfColor  // text color
+	background  *pdfColor // page background, nil for none
+	headerColor pdfColor  // text color of headers
+	headerBg    *pdfColor // background of headers, nil for none
+	headerLineW float64   // width of the line below headers
+	headerLineC pdfColor  // color of the line below headers
+	stripe      *pdfColor // background of every other row, nil for none
+	lineW       float64   // width of separator lines
+	lineC       pdfColor  // color of separator lines
+}
+
+func (pt *PDFTable) writeNativeOutput(w io.Writer) error {
+	pageW, pageH := pt.getPageSize()
+	l := &pdfLayout{
+		doc:    newPDFDoc(pageW, pageH),
+		left:   pt.Options.MarginLeft * PDFPTPERMM,
+		right:  pageW - pt.Options.MarginRight*PDFPTPERMM,
+		top:    pt.Options.MarginTop * PDFPTPERMM,
+		bottom: pageH - pt.Options.MarginBottom*PDFPTPERMM,
+	}
+	pt.setNativeTheme(l)
+	l.doc.addInfo("Title", pt.getTitle())
+	l.doc.addInfo("Author", pt.Options.Author)
+	l.doc.addInfo("Subject", pt.Options.Subject)
+	pt.addNativePage(l)
+
+	// title and sections are printed on the first page only
+	pt.writeNativeText(l, pt.Table.GetTitle(), true, 14)
+	pt.writeNativeText(l, pt.Table.GetSection1(), false, 11)
+	pt.writeNativeText(l, pt.Table.GetSection2(), false, 10)
+	pt.writeNativeText(l, pt.Table.GetSection3(), false, 9)
+	l.y += l.lineH
+
+	if err := pt.Table.HasHeaders(); err != nil {
+		pt.writeNativeText(l, err.Error(), false, l.fontSize)
+	} else {
+		pt.setNativeColumns(l)
+
+		// headers and footer are repeated on each page
+		l.headerFun = func() { pt.writeNativeHeaders(l) }
+		l.footerFun = func() { pt.writeNativeFooter(l) }
+		l.footerH = pt.getNativeFooterHeight(l)
+		l.headerFun()
+
+		if err := pt.Table.HasData(); err != nil {
+			pt.writeNativeText(l, err.Error(), false, l.fontSize)
+		} else {
+			for i := 0; i < pt.Table.RowCount(); i++ {
+				// give up as soon as the caller is not interested anymore
+				if err := pt.getContext().Err(); err != nil {
+					return err
+				}
+				pt.writeNativeRow(l, i)
+			}
+		}
+		// footer, e.g. a count of 0, is shown without rows too
+		l.footerFun()
+	}
+
+	pt.writeNativeHeaderFooter(l)
+
+	if err := l.doc.write(&pt.outbuf); err != nil {
+		return err
+	}
+
+	// write output to passed io.Writer interface object
+	_, err := w.Write(pt.outbuf.Bytes())
+	return err
+}
+
+// setNativeTheme sets the fonts, colors and lines of the layout from the
+// table theme, defaults are used for anything not set in the theme
+func (pt *PDFTable) setNativeTheme(l *pdfLayout) {
+	l.fontSize = PDFFONTSIZE
+	l.headerLineW = 1.5
+	l.lineW = 0.5
+
+	family := pt.Options.FontName
+	th := pt.Table.theme
+	if th != nil {
+		if family == "" {
+			if f := strings.ToLower(th.FontFamily); strings.Contains(f, "courier") || strings.Contains(f, "monospace") {
+				family = "Courier"
+			}
+		}
+		// theme font size is in px for html, scale it against html base font size
+		if px, ok := parseCSSPixels(th.FontSize); ok {
+			l.fontSize = PDFFONTSIZE * px / CSSFONTSIZE
+		}
+		l.color, _ = parseCSSColor(th.Color)
+		l.headerColor = l.color
+		if c, ok := parseCSSColor(th.HeaderColor); ok {
+			l.headerColor = c
+		}
+		if c, ok := parseCSSColor(th.Background); ok {
+			l.background = &c
+		}
+		if c, ok := parseCSSColor(th.HeaderBackground); ok {
+			l.headerBg = &c
+		}
+		if c, ok := parseCSSColor(th.StripeBackground); ok {
+			l.stripe = &c
+		}
+		if w, c, ok := parseCSSBorder(th.HeaderBorder); ok {
+			l.headerLineW, l.headerLineC = w, c
+		}
+		if w, c, ok := parseCSSBorder(th.LineBorder); ok {
+			l.lineW, l.lineC = w, c
+		}
+	}
+
+	l.lineH = l.fontSize * 1.25
+	l.doc.setFamily(family)
+	l.doc.setColor(l.color)
+}
+
+// addNativePage starts a new page with the page background of the theme
+func (pt *PDFTable) addNativePage(l *pdfLayout) {
+	l.doc.addPage()
+	if l.background != nil {
+		l.doc.rect(0, 0, l.doc.width, l.doc.height, *l.background)
+	}
+	l.y = l.top
+}
+
+// setNativeColumns distributes the printable page width over the columns
+// in proportion of their widths
+func (pt *PDFTable) setNativeColumns(l *pdfLayout) {
+	var total int
+	for i := 0; i < pt.Table.ColCount(); i++ {
+		total += pt.getNativeColWidth(i)
+	}
+	x := l.left
+	for i := 0; i < pt.Table.ColCount(); i++ {
+		w := (l.right - l.left) * float64(pt.getNativeColWidth(i)) / float64(total)
+		l.colX = append(l.colX, x)
+		l.colW = append(l.colW, w)
+		x += w
+	}
+}
+
+// getNativeColWidth returns the relative width of a column
+func (pt *PDFTable) getNativeColWidth(col int) int {
+	w := pt.Table.ColDefs[col].Width
+	if pt.Table.ColDefs[col].HTMLWidth != -1 {
+		w = pt.Table.ColDefs[col].HTMLWidth
+	}
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// writeNativeText writes centered and wrapped text across the page
+func (pt *PDFTable) writeNativeText(l *pdfLayout, s string, bold bool, size float64) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	l.doc.setFont(bold, size)
+	for _, line := range l.doc.wrapText(s, l.right-l.left) {
+		l.y += size * 1.25
+		l.doc.text(l.left+(l.right-l.left-l.doc.stringWidth(line))/2, l.y-size*0.25, line)
+	}
+}
+
+func (pt *PDFTable) writeNativeHeaders(l *pdfLayout) {
+	// header groups are stacked above the column headers
+	for _, groups := range pt.Table.getHeaderGroupRows() {
+		pt.writeNativeHeaderGroups(l, groups)
+	}
+
+	var cells [][]string
+	l.doc.setFont(true, l.fontSize)
+	for i := 0; i < pt.Table.ColCount(); i++ {
+		cells = append(cells, l.doc.wrapText(pt.Table.ColDefs[i].ColTitle, l.colW[i]-2*PDFCELLPADDING))
+	}
+	if l.headerBg != nil {
+		l.doc.rect(l.left, l.y, l.right-l.left, pt.getNativeHeight(l, cells), *l.headerBg)
+	}
+	l.doc.setColor(l.headerColor)
+	pt.writeNativeCells(l, cells, l.colW, true, false)
+	l.doc.setColor(l.color)
+	pt.writeNativeLine(l, l.headerLineW, l.headerLineC)
+}
+
+// writeNativeHeaderGroups draws a row of header groups, titles are centered
+// over their columns and underlined
+func (pt *PDFTable) writeNativeHeaderGroups(l *pdfLayout, groups []HeaderGroup) {
+	cells := make([][]string, pt.Table.ColCount())
+	widths := append([]float64(nil), l.colW...)
+	l.doc.setFont(true, l.fontSize)
+	for _, g := range groups {
+		widths[g.FromCol] = l.colX[g.ToCol] + l.colW[g.ToCol] - l.colX[g.FromCol]
+		if g.Title != "" {
+			cells[g.FromCol] = l.doc.wrapText(g.Title, widths[g.FromCol]-2*PDFCELLPADDING)
+		}
+	}
+	if l.headerBg != nil {
+		l.doc.rect(l.left, l.y, l.right-l.left, pt.getNativeHeight(l, cells), *l.headerBg)
+	}
+	l.doc.setColor(l.headerColor)
+	pt.writeNativeCells(l, cells, widths, true, true)
+	l.doc.setColor(l.color)
+
+	l.doc.setLineColor(l.headerLineC)
+	for _, g := range groups {
+		if g.Title != "" {
+			l.doc.line(l.colX[g.FromCol]+PDFCELLPADDING, l.y, l.colX[g.FromCol]+widths[g.FromCol]-PDFCELLPADDING, l.y, l.lineW)
+		}
+	}
+}
+
+// writeNativeLine draws a line across the table at current position
+func (pt *PDFTable) writeNativeLine(l *pdfLayout, width float64, c pdfColor) {
+	l.doc.setLineColor(c)
+	l.doc.line(l.left, l.y, l.right, l.y, width)
+}
+
+func (pt *PDFTable) writeNativeRow(l *pdfLayout, row int) {
+	l.doc.setFont(false, l.fontSize)
+	cells, widths := pt.getNativeCells(l, row, pt.Table.Row[row])
+
+	// move to next page if the row doesn't fit, rows are never split
+	h := pt.getNativeHeight(l, cells)
+	if l.y+h > l.bottom-l.footerH {
+		l.footerFun()
+		pt.addNativePage(l)
+		l.headerFun()
+	}
+
+	// same as html version, even rows are striped
+	if l.stripe != nil && row%2 == 1 {
+		l.doc.rect(l.left, l.y, l.right-l.left, h, *l.stripe)
+	}
+
+	// same as text version, line before is discarded if previous row has a line after
+	if pt.Table.hasLineBefore(row) && !(row > 0 && pt.Table.hasLineAfter(row-1)) {
+		pt.writeNativeLine(l, l.lineW, l.lineC)
+	}
+
+	l.doc.setFont(false, l.fontSize)
+	pt.writeNativeCells(l, cells, widths, false, false)
+
+	if pt.Table.hasLineAfter(row) {
+		pt.writeNativeLine(l, l.lineW, l.lineC)
+	}
+}
+
+// getNativeCells returns the wrapped lines in current font and the width of the
+// cells of a row, row is the index of the row in the table, it is used to find
+// merged cells
+func (pt *PDFTable) getNativeCells(l *pdfLayout, row int, cs Colset) ([][]string, []float64) {
+	var cells [][]string
+	widths := append([]float64(nil), l.colW...)
+	for i := 0; i < pt.Table.ColCount(); i++ {
+		// merged cell is written in its top row across all of its columns,
+		// the cells hidden by it stay blank
+		if m := pt.Table.getMerge(row, i); m != nil {
+			if m.Row != row || m.Col != i {
+				cells = append(cells, nil)
+				continue
+			}
+			widths[i] = l.colX[m.Col+m.ColSpan-1] + l.colW[m.Col+m.ColSpan-1] - l.colX[i]
+		}
+		if cs.Col[i].Type == CELLSTRING {
+			cells = append(cells, l.doc.wrapText(cs.Col[i].Sval, widths[i]-2*PDFCELLPADDING))
+		} else {
+			cells = append(cells, []string{pt.Table.cellText(cs.Col[i], i)})
+		}
+	}
+	return cells, widths
+}
+
+// getNativeFooterHeight returns the height of all footer rows
+func (pt *PDFTable) getNativeFooterHeight(l *pdfLayout) float64 {
+	var h float64
+	l.doc.setFont(true, l.fontSize)
+	for i := 0; i < len(pt.Table.Footer); i++ {
+		cells, _ := pt.getNativeCells(l, -1, pt.Table.getFooterRow(i))
+		h += pt.getNativeHeight(l, cells)
+	}
+	return h
+}
+
+// writeNativeFooter draws the footer rows in bold after a separator line
+func (pt *PDFTable) writeNativeFooter(l *pdfLayout) {
+	if len(pt.Table.Footer) == 0 {
+		return
+	}
+	pt.writeNativeLine(l, l.lineW, l.lineC)
+	l.doc.setFont(true, l.fontSize)
+	for i := 0; i < len(pt.Table.Footer); i++ {
+		cells, widths := pt.getNativeCells(l, -1, pt.Table.getFooterRow(i))
+		pt.writeNativeCells(l, cells, widths, false, false)
+	}
+}
+
+// getNativeHeight returns the height of a row of cells
+func (pt *PDFTable) getNativeHeight(l *pdfLayout, cells [][]string) float64 {
+	lines := 1
+	for _, c := range cells {
+		if len(c) > lines {
+			lines = len(c)
+		}
+	}
+	return float64(lines)*l.lineH + 2*PDFCELLPADDING
+}
+
+// writeNativeCells draws a row of cells at current position and moves past it,
+// widths holds the width of each cell which is wider than its column if merged.
+// Cells are justified as their columns unless they are centered.
+func (pt *PDFTable) writeNativeCells(l *pdfLayout, cells [][]string, widths []float64, bottomAlign, center bool) {
+	h := pt.getNativeHeight(l, cells)
+	for i, c := range cells {
+		top := l.y + PDFCELLPADDING
+		if bottomAlign {
+			// multi line headers are aligned to the bottom like in text version
+			top = l.y + h - PDFCELLPADDING - float64(len(c))*l.lineH
+		}
+		for j, line := range c {
+			x := l.colX[i] + PDFCELLPADDING
+			if center {
+				x = l.colX[i] + (widths[i]-l.doc.stringWidth(line))/2
+			} else if pt.Table.ColDefs[i].Justify == COLJUSTIFYRIGHT {
+				x = l.colX[i] + widths[i] - PDFCELLPADDING - l.doc.stringWidth(line)
+			}
+			l.doc.text(x, top+float64(j+1)*l.lineH-l.lineH*0.25, line)
+		}
+	}
+	l.y += h
+}
+
+// writeNativeHeaderFooter writes header and footer on each page, page count
+// is known only after all the rows are laid out
+func (pt *PDFTable) writeNativeHeaderFooter(l *pdfLayout) {
+	size := pt.Options.FontSize
+	if size <= 0 {
+		size = 7
+	}
+	timestamp := pt.getTimestamp()
+	total := len(l.doc.pages)
+
+	// header sits right above the top margin, footer right below the bottom margin
+	headerY := l.top - size/2
+	footerY := l.bottom + 5 + size
+
+	for i := 0; i < total; i++ {
+		l.doc.setPage(i)
+		l.doc.setFont(false, size)
+
+		r := strings.NewReplacer(PDFPAGENUMBER, strconv.Itoa(i+1), PDFPAGECOUNT, strconv.Itoa(total))
+		text := func(s string, y float64, align string) {
+			s = r.Replace(pt.replacePlaceholders(s, timestamp))
+			if s == "" {
+				return
+			}
+			x := l.left
+			switch align {
+			case "center":
+				x = l.left + (l.right-l.left-l.doc.stringWidth(s))/2
+			case "right":
+				x = l.right - l.doc.stringWidth(s)
+			}
+			l.doc.text(x, y, s)
+		}
+
+		text(pt.Options.HeaderLeft, headerY, "left")
+		text(pt.Options.HeaderCenter, headerY, "center")
+		text(pt.Options.HeaderRight, headerY, "right")
+		text(pt.Options.FooterLeft, footerY, "left")
+		text(pt.Options.FooterCenter, footerY, "center")
+		text(pt.Options.FooterRight, footerY, "right")
+	}
+}
@@ -0,0 +1,183 @@
+package gotable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Collations of strings used by SortKey
+const (
+	COLLATENOCASE  = 0 // case-insensitive, the default, ties are broken case-sensitively
+	COLLATECASE    = 1 // case-sensitive, byte by byte
+	COLLATENATURAL = 2 // case-insensitive, runs of digits compare by their numeric value, e.g. "a2" < "a10"
+)
+
+// StringComparer compares strings, e.g. for a locale. The Collator of
+// golang.org/x/text/collate implements it.
+type StringComparer interface {
+	CompareString(a, b string) int
+}
+
+// SortKey describes a column to sort by. Empty cells are nulls, they are placed
+// last unless NullsFirst is set, regardless of the direction. Cells of different
+// types are ordered by type, except for ints and floats which compare as numbers.
+type SortKey struct {
+	Col        int                 // column index
+	Key        string              // column key, if set it is used instead of Col
+	Descending bool                // sort from largest to smallest
+	NullsFirst bool                // place empty cells first
+	Collation  int                 // COLLATENOCASE, COLLATECASE or COLLATENATURAL
+	Collator   StringComparer      // if set, compares strings instead of Collation
+	Compare    func(a, b Cell) int // if set, compares non-empty cells instead of all of the above, returns <0, 0 or >0
+}
+
+// compare returns <0, 0 or >0 when a sorts before, with or after b
+func (k *SortKey) compare(a, b Cell) int {
+	if a.Type == 0 || b.Type == 0 {
+		c := compareInts(int64(b.Type), int64(a.Type)) // nulls last
+		if k.NullsFirst {
+			c = -c
+		}
+		return c
+	}
+	var c int
+	switch {
+	case k.Compare != nil:
+		c = k.Compare(a, b)
+	case a.Type == CELLSTRING && b.Type == CELLSTRING:
+		c = k.compareStrings(a.Sval, b.Sval)
+	default:
+		c = compareCells(a, b)
+	}
+	if k.Descending {
+		c = -c
+	}
+	return c
+}
+
+func (k *SortKey) compareStrings(a, b string) int {
+	if k.Collator != nil {
+		return k.Collator.CompareString(a, b)
+	}
+	switch k.Collation {
+	case COLLATECASE:
+		return strings.Compare(a, b)
+	case COLLATENATURAL:
+		if c := compareNatural(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	}
+	return compareCells(Cell{Type: CELLSTRING, Sval: a}, Cell{Type: CELLSTRING, Sval: b})
+}
+
+// compareNatural compares runs of digits by their numeric value and anything
+// else case-insensitively
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if isDigit(ra) && isDigit(rb) {
+			da, db := digitRun(a), digitRun(b)
+			a, b = a[len(da):], b[len(db):]
+			da, db = strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if c := compareInts(int64(len(da)), int64(len(db))); c != 0 {
+				return c
+			}
+			if c := strings.Compare(da, db); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := compareInts(int64(unicode.ToLower(ra)), int64(unicode.ToLower(rb))); c != 0 {
+			return c
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return compareInts(int64(len(a)), int64(len(b)))
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// digitRun returns the leading digits of s
+func digitRun(s string) string {
+	i := 0
+	for i < len(s) && isDigit(rune(s[i])) {
+		i++
+	}
+	return s[:i]
+}
+
+// SortBy sorts rows from thru to by the supplied keys, the first key decides
+// unless cells are equal, then the next key decides and so on. The sort is
+// stable, rows equal by all keys keep their order. Css, rowsets and merges
+// follow their rows, separator lines stay where they are. A merge spanning rows
+// moves only if its rows stay together, if the sort would split it the table is
+// left unchanged and an error is returned.
+func (t *Table) SortBy(from, to int, keys ...SortKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("No keys to sort by")
+	}
+	keys = append([]SortKey(nil), keys...)
+	for i := range keys {
+		if keys[i].Key != "" {
+			col, err := t.ColumnIndex(keys[i].Key)
+			if err != nil {
+				return err
+			}
+			keys[i].Col = col
+		}
+		if err := t.HasValidColumn(keys[i].Col); err != nil {
+			return err
+		}
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(t.Row) {
+		to = len(t.Row) - 1
+	}
+	if from >= to {
+		return nil
+	}
+
+	idx := make([]int, len(t.Row))
+	for i := range idx {
+		idx[i] = i
+	}
+	r := idx[from : to+1]
+	sort.SliceStable(r, func(i, j int) bool {
+		for k := range keys {
+			if c := keys[k].compare(t.Row[r[i]].Col[keys[k].Col], t.Row[r[j]].Col[keys[k].Col]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	newIdx := make([]int, len(idx)) // new index of each row
+	for i, j := range idx {
+		newIdx[j] = i
+	}
+	for _, m := range t.Merges {
+		for j := 1; j < m.RowSpan; j++ {
+			if newIdx[m.Row+j] != newIdx[m.Row]+j {
+				return fmt.Errorf("Sort would split the merge at row: %d, column: %d", m.Row, m.Col)
+			}
+		}
+	}
+
+	rows := make([]Colset, len(idx))
+	for i, j := range idx {
+		rows[i] = t.Row[j]
+	}
+	lineAfter, lineBefore := t.LineAfter, t.LineBefore
+	t.reorderRows(rows, idx)
+	t.LineAfter, t.LineBefore = lineAfter, lineBefore
+	return nil
+}
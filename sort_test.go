@@ -0,0 +1,132 @@
+package gotable
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// reverseCollator compares strings in reverse order, like a locale would
+type reverseCollator struct{}
+
+func (reverseCollator) CompareString(a, b string) int { return strings.Compare(b, a) }
+
+func TestSortBy(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Seq", 4, CELLINT, COLJUSTIFYRIGHT)
+	data := []struct {
+		name   string
+		amount interface{}
+	}{{"item10", 2.5}, {"Item2", nil}, {"item1", 7}, {"ITEM2", 2.5}, {"item2", 1.0}}
+	for i, d := range data {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, d.name)
+		switch v := d.amount.(type) {
+		case float64:
+			tbl.Putf(-1, 1, v)
+		case int:
+			tbl.Puti(-1, 1, int64(v)) // ints compare with floats
+		}
+		tbl.Puti(-1, 2, int64(i))
+	}
+	tbl.AddLineAfter(2)
+	tbl.SetRowCSS(1, []*CSSProperty{{Name: "color", Value: "red"}})
+
+	seq := func() []int64 {
+		var s []int64
+		for i := 0; i < tbl.RowCount(); i++ {
+			s = append(s, tbl.Geti(i, 2))
+		}
+		return s
+	}
+	for _, c := range []struct {
+		keys []SortKey
+		want []int64
+	}{
+		{[]SortKey{{Col: 0}}, []int64{2, 0, 3, 1, 4}},
+		{[]SortKey{{Col: 0, Collation: COLLATECASE}}, []int64{3, 1, 2, 0, 4}},
+		{[]SortKey{{Col: 0, Collation: COLLATENATURAL}}, []int64{2, 3, 1, 4, 0}},
+		{[]SortKey{{Col: 0, Collator: reverseCollator{}}}, []int64{4, 0, 2, 1, 3}},
+		{[]SortKey{{Col: 1}}, []int64{4, 0, 3, 2, 1}},
+		{[]SortKey{{Col: 1, Descending: true}}, []int64{2, 0, 3, 4, 1}},
+		{[]SortKey{{Col: 1, Descending: true, NullsFirst: true}}, []int64{1, 2, 0, 3, 4}},
+		{[]SortKey{{Col: 1}, {Col: 2, Descending: true}}, []int64{4, 3, 0, 2, 1}},
+		{[]SortKey{{Col: 0, Compare: func(a, b Cell) int { return len(a.Sval) - len(b.Sval) }}}, []int64{1, 2, 3, 4, 0}},
+	} {
+		tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 2})
+		if err := tbl.SortBy(0, tbl.RowCount()-1, c.keys...); err != nil {
+			t.Errorf("sort_test: Error sorting by %v: %s\n", c.keys, err.Error())
+		}
+		if s := seq(); !reflect.DeepEqual(s, c.want) {
+			t.Errorf("sort_test: Expected order %v sorting by %v, found %v\n", c.want, c.keys, s)
+		}
+	}
+
+	// only the range is sorted, lines stay, css follows its row
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 2})
+	if err := tbl.SortBy(1, 3, SortKey{Col: 2, Descending: true}); err != nil {
+		t.Errorf("sort_test: Error sorting rows 1-3: %s\n", err.Error())
+	}
+	if s := seq(); !reflect.DeepEqual(s, []int64{0, 3, 2, 1, 4}) {
+		t.Errorf("sort_test: Expected rows 1-3 to be reversed, found %v\n", s)
+	}
+	if !reflect.DeepEqual(tbl.LineAfter, []int{2}) {
+		t.Errorf("sort_test: Expected line after row 2, found %v\n", tbl.LineAfter)
+	}
+	if _, ok := tbl.CSS["row-3"]; !ok {
+		t.Errorf("sort_test: Expected row css to move to row 3, found %v\n", tbl.CSS)
+	}
+
+	// merges move with their rows, splitting one is an error
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 2})
+	tbl.MergeCells(3, 0, 2, 1)
+	if err := tbl.SortBy(0, 4, SortKey{Col: 2, Descending: true}); err == nil {
+		t.Errorf("sort_test: Expected error for splitting a merge\n")
+	}
+	if s := seq(); !reflect.DeepEqual(s, []int64{0, 1, 2, 3, 4}) || !reflect.DeepEqual(tbl.Merges, []CellMerge{{3, 0, 2, 1}}) {
+		t.Errorf("sort_test: Expected table unchanged by failed sort, found %v %v\n", s, tbl.Merges)
+	}
+	if err := tbl.SortBy(2, 4, SortKey{Col: 2, Descending: true}); err == nil {
+		t.Errorf("sort_test: Expected error for splitting a merge\n")
+	}
+	if err := tbl.SortBy(0, 2, SortKey{Col: 2, Descending: true}); err != nil {
+		t.Errorf("sort_test: Error sorting rows outside a merge: %s\n", err.Error())
+	}
+	if !reflect.DeepEqual(tbl.Merges, []CellMerge{{3, 0, 2, 1}}) {
+		t.Errorf("sort_test: Expected merge to stay at row 3, found %v\n", tbl.Merges)
+	}
+	tbl.UnmergeCells(3, 0)
+
+	if err := tbl.SortBy(0, 4); err == nil {
+		t.Errorf("sort_test: Expected error for no keys\n")
+	}
+	if err := tbl.SortBy(0, 4, SortKey{Col: 3}); err == nil {
+		t.Errorf("sort_test: Expected error for invalid column\n")
+	}
+}
+
+func TestSortByLarge(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Key", 10, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Seq", 10, CELLINT, COLJUSTIFYRIGHT)
+	n := 100000
+	for i := 0; i < n; i++ {
+		tbl.AddRow()
+		tbl.Puti(-1, 0, int64(rand.Intn(100)))
+		tbl.Puti(-1, 1, int64(i))
+	}
+	if err := tbl.SortBy(0, n-1, SortKey{Col: 0}); err != nil {
+		t.Fatalf("sort_test: Error sorting: %s\n", err.Error())
+	}
+	for i := 1; i < n; i++ {
+		k0, k1 := tbl.Geti(i-1, 0), tbl.Geti(i, 0)
+		if k0 > k1 || (k0 == k1 && tbl.Geti(i-1, 1) > tbl.Geti(i, 1)) {
+			t.Fatalf("sort_test: Rows %d and %d are out of order or not stable\n", i-1, i)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package gotable
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLStream is an option of FromSQLRows to write the rows to W in the export
+// format Format as they are read, rather than to keep them in the table. Only
+// formats which are written row by row can be streamed, FORMATCSV and
+// FORMATJSON. Json rows are written in Layout, JSONROWOBJECT (default) or
+// JSONROWARRAY.
+type SQLStream struct {
+	W      io.Writer
+	Format string
+	Layout int
+}
+
+// sqlIntTypes are the database type names of integer columns
+var sqlIntTypes = map[string]bool{
+	"INT": true, "INTEGER": true, "BIGINT": true, "SMALLINT": true, "TINYINT": true, "MEDIUMINT": true,
+	"INT2": true, "INT4": true, "INT8": true,
+}
+
+// sqlDateLayouts are the layouts of dates and datetimes which drivers return as text
+var sqlDateLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05", "2006-01-02"}
+
+// FromSQLRows returns a new table with a column for each column of the result
+// set rows, titled by its name, and a row for each row read from rows. The type
+// of a column is derived from its ColumnTypes, CELLINT, CELLFLOAT, CELLDATE or
+// CELLDATETIME, anything else is CELLSTRING. NULL values are empty cells, not
+// zeros, values which can't be converted to the type of their column are put
+// as strings. Options are a string, the title of the table, and SQLStream. If
+// streamed, the returned table has no rows. Rows are not closed.
+func FromSQLRows(rows *sql.Rows, opts ...interface{}) (*Table, error) {
+	var t Table
+	t.Init()
+	var stream *SQLStream
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case string:
+			t.SetTitle(v)
+		case SQLStream:
+			stream = &v
+		default:
+			return nil, fmt.Errorf("Unsupported option %T for FromSQLRows", opt)
+		}
+	}
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, ct := range types {
+		celltype := sqlCellType(ct)
+		width, justify := 10, COLJUSTIFYLEFT
+		switch celltype {
+		case CELLINT, CELLFLOAT:
+			justify = COLJUSTIFYRIGHT
+		case CELLDATETIME:
+			width = len(t.DateTimeFmt)
+		}
+		if len(ct.Name()) > width {
+			width = len(ct.Name())
+		}
+		t.AddColumn(ct.Name(), width, celltype, justify)
+	}
+
+	var tout TableExportType
+	var sep string
+	if stream != nil {
+		switch strings.ToLower(stream.Format) {
+		case FORMATCSV:
+			ct := &CSVTable{Table: &t, CellSep: ","}
+			s := ct.getTitle() + ct.getSection1() + ct.getSection2() + ct.getSection3()
+			hdr, err := ct.getHeaders()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.WriteString(stream.W, s+hdr); err != nil {
+				return nil, err
+			}
+			tout = ct
+		case FORMATJSON:
+			if stream.Layout == 0 {
+				stream.Layout = JSONROWOBJECT
+			}
+			if err := checkJSONLayout(stream.Layout); err != nil {
+				return nil, err
+			}
+			jt := &JSONTable{Table: &t, RowLayout: stream.Layout}
+			hdr, err := jt.getHeaders()
+			if err != nil {
+				return nil, err
+			}
+			s := `{"title":` + jt.getTitle() + `,"section1":` + jt.getSection1() + `,"section2":` + jt.getSection2() +
+				`,"section3":` + jt.getSection3() + `,"columns":` + hdr + `,"rows":[`
+			if _, err := io.WriteString(stream.W, s); err != nil {
+				return nil, err
+			}
+			tout, sep = jt, ","
+		default:
+			return nil, fmt.Errorf("Export format %s cannot be streamed", stream.Format)
+		}
+	}
+
+	values := make([]interface{}, len(types))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	for n := 0; rows.Next(); n++ {
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		if stream != nil {
+			t.Row = t.Row[:0]
+		}
+		t.AddRow()
+		for col, v := range values {
+			t.putSQLValue(col, *(v.(*interface{})))
+		}
+		if stream != nil {
+			s, _ := tout.getRow(0)
+			if n > 0 {
+				s = sep + s
+			}
+			if _, err := io.WriteString(stream.W, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stream != nil {
+		t.Row = nil
+		if sep != "" {
+			if _, err := io.WriteString(stream.W, `]}`+NEWLINE); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &t, nil
+}
+
+// sqlCellType returns the cell type of the values of a result set column
+func sqlCellType(ct *sql.ColumnType) int {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if st := ct.ScanType(); st != nil {
+		if st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		switch st {
+		case reflect.TypeOf(time.Time{}), reflect.TypeOf(sql.NullTime{}):
+			if name == "DATE" {
+				return CELLDATE
+			}
+			return CELLDATETIME
+		case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}):
+			return CELLINT
+		case reflect.TypeOf(sql.NullFloat64{}):
+			return CELLFLOAT
+		}
+		switch st.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return CELLINT
+		case reflect.Float32, reflect.Float64:
+			return CELLFLOAT
+		case reflect.String, reflect.Bool:
+			return CELLSTRING
+		}
+	}
+
+	// drivers which scan into interface{} or []byte tell by the type name
+	switch {
+	case name == "DATE":
+		return CELLDATE
+	case strings.Contains(name, "TIMESTAMP") || strings.Contains(name, "DATETIME"):
+		return CELLDATETIME
+	case sqlIntTypes[strings.TrimSuffix(strings.TrimPrefix(name, "UNSIGNED "), " UNSIGNED")]:
+		return CELLINT
+	case strings.Contains(name, "FLOAT") || strings.Contains(name, "DOUBLE") || name == "REAL" ||
+		strings.Contains(name, "DECIMAL") || strings.Contains(name, "NUMERIC"):
+		return CELLFLOAT
+	}
+	return CELLSTRING
+}
+
+// putSQLValue puts value v, as returned by a driver, in column col of the last
+// row, converted to the type of the column. nil, i.e. NULL, leaves it empty.
+func (t *Table) putSQLValue(col int, v interface{}) {
+	celltype := t.ColDefs[col].CellType
+	switch x := v.(type) {
+	case nil:
+	case int64:
+		if celltype == CELLFLOAT {
+			t.Putf(-1, col, float64(x))
+		} else {
+			t.Puti(-1, col, x)
+		}
+	case float64:
+		if celltype == CELLINT {
+			t.Puti(-1, col, int64(x))
+		} else {
+			t.Putf(-1, col, x)
+		}
+	case time.Time:
+		if celltype == CELLDATE {
+			t.Putd(-1, col, x)
+		} else {
+			t.Putdt(-1, col, x)
+		}
+	case bool:
+		t.Puts(-1, col, strconv.FormatBool(x))
+	case []byte:
+		t.putSQLText(col, string(x))
+	case string:
+		t.putSQLText(col, x)
+	default:
+		t.Puts(-1, col, fmt.Sprint(x))
+	}
+}
+
+// putSQLText puts s in column col of the last row, parsed as the type of the column
+func (t *Table) putSQLText(col int, s string) {
+	switch t.ColDefs[col].CellType {
+	case CELLINT:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			t.Puti(-1, col, i)
+			return
+		}
+	case CELLFLOAT:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			t.Putf(-1, col, f)
+			return
+		}
+	case CELLDATE, CELLDATETIME:
+		for _, layout := range sqlDateLayouts {
+			if d, err := time.Parse(layout, s); err == nil {
+				t.putSQLValue(col, d)
+				return
+			}
+		}
+	}
+	t.Puts(-1, col, s)
+}
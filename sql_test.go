@@ -0,0 +1,210 @@
+package gotable
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeColumn is a result set column of the fake driver
+type fakeColumn struct {
+	name, dbType string
+	scanType     reflect.Type
+}
+
+// fake driver returning fakeColumns and fakeRows for any query
+var (
+	fakeColumns = []fakeColumn{
+		{"id", "INTEGER", reflect.TypeOf(int64(0))},
+		{"name", "VARCHAR", reflect.TypeOf("")},
+		{"amount", "DECIMAL", reflect.TypeOf([]byte(nil))},
+		{"born", "DATE", reflect.TypeOf(time.Time{})},
+		{"updated", "TIMESTAMP", reflect.TypeOf(sql.NullTime{})},
+		{"score", "DOUBLE", reflect.TypeOf(sql.NullFloat64{})},
+	}
+	fakeRows = [][]driver.Value{
+		{int64(1), "acme", []byte("12.50"), time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC), "2017-03-02 10:30:00", 0.5},
+		{int64(2), nil, nil, nil, nil, nil},
+		{int64(3), "globex", []byte("n/a"), time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2017, time.April, 2, 8, 0, 0, 0, time.UTC), int64(7)},
+	}
+)
+
+type fakeDriver struct{}
+type fakeConn struct{}
+type fakeStmt struct{}
+type fakeRowsIter struct{ i int }
+
+func (fakeDriver) Open(name string) (driver.Conn, error)   { return fakeConn{}, nil }
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, fmt.Errorf("not supported") }
+func (fakeStmt) Close() error                              { return nil }
+func (fakeStmt) NumInput() int                             { return 0 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("not supported")
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return &fakeRowsIter{}, nil }
+func (r *fakeRowsIter) Close() error                            { return nil }
+func (r *fakeRowsIter) Columns() []string {
+	var names []string
+	for _, c := range fakeColumns {
+		names = append(names, c.name)
+	}
+	return names
+}
+func (r *fakeRowsIter) Next(dest []driver.Value) error {
+	if r.i >= len(fakeRows) {
+		return io.EOF
+	}
+	copy(dest, fakeRows[r.i])
+	r.i++
+	return nil
+}
+func (r *fakeRowsIter) ColumnTypeScanType(i int) reflect.Type   { return fakeColumns[i].scanType }
+func (r *fakeRowsIter) ColumnTypeDatabaseTypeName(i int) string { return fakeColumns[i].dbType }
+
+func init() {
+	sql.Register("gotablefake", fakeDriver{})
+}
+
+// fakeQuery returns the rows of a query of the fake driver
+func fakeQuery(t *testing.T) *sql.Rows {
+	db, err := sql.Open("gotablefake", "")
+	if err != nil {
+		t.Fatalf("sql_test: Error opening database: %s\n", err.Error())
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query("SELECT * FROM fake")
+	if err != nil {
+		t.Fatalf("sql_test: Error querying database: %s\n", err.Error())
+	}
+	return rows
+}
+
+func TestFromSQLRows(t *testing.T) {
+	rows := fakeQuery(t)
+	defer rows.Close()
+	tbl, err := FromSQLRows(rows, "Customers")
+	if err != nil {
+		t.Fatalf("sql_test: Error creating table: %s\n", err.Error())
+	}
+
+	var types []int
+	for _, cd := range tbl.ColDefs {
+		types = append(types, cd.CellType)
+	}
+	if !reflect.DeepEqual(types, []int{CELLINT, CELLSTRING, CELLFLOAT, CELLDATE, CELLDATETIME, CELLFLOAT}) || tbl.ColDefs[4].ColTitle != "updated" {
+		t.Errorf("sql_test: Unexpected column types %v\n", types)
+	}
+	if tbl.Title != "Customers" || tbl.RowCount() != 3 || tbl.Geti(0, 0) != 1 || tbl.Gets(0, 1) != "acme" || tbl.Getf(0, 2) != 12.5 ||
+		!tbl.Getd(0, 4).Equal(time.Date(2017, time.March, 2, 10, 30, 0, 0, time.UTC)) || tbl.Getf(2, 5) != 7 {
+		t.Errorf("sql_test: Unexpected rows %v\n", tbl.Row)
+	}
+	// NULLs are empty cells, unconvertible values are strings
+	for col := 1; col < 6; col++ {
+		if c := tbl.Get(1, col); c.Type != 0 {
+			t.Errorf("sql_test: Expected empty cell for NULL in column %d, found %#v\n", col, c)
+		}
+	}
+	if c := tbl.Get(2, 2); c.Type != CELLSTRING || c.Sval != "n/a" {
+		t.Errorf("sql_test: Expected string n/a, found %#v\n", c)
+	}
+	if c := tbl.Aggregate(2, AggCount); c.Ival != 2 {
+		t.Errorf("sql_test: Expected 2 values, found %#v\n", c)
+	}
+}
+
+func TestFromSQLRowsTypeNames(t *testing.T) {
+	// drivers scanning into interface{} tell the type by its name only
+	columns, rows := fakeColumns, fakeRows
+	defer func() { fakeColumns, fakeRows = columns, rows }()
+	iface := reflect.TypeOf((*interface{})(nil)).Elem()
+	want := map[string]int{
+		"INTEGER": CELLINT, "BIGINT": CELLINT, "INT4": CELLINT, "UNSIGNED TINYINT": CELLINT, "INT UNSIGNED": CELLINT,
+		"INTERVAL": CELLSTRING, "POINT": CELLSTRING, "PRINTABLE": CELLSTRING, "NUMERIC": CELLFLOAT,
+	}
+	fakeColumns, fakeRows = nil, [][]driver.Value{nil}
+	for name := range want {
+		fakeColumns = append(fakeColumns, fakeColumn{strings.ToLower(name), name, iface})
+		fakeRows[0] = append(fakeRows[0], "1 day")
+	}
+
+	sqlRows := fakeQuery(t)
+	defer sqlRows.Close()
+	tbl, err := FromSQLRows(sqlRows)
+	if err != nil {
+		t.Fatalf("sql_test: Error creating table: %s\n", err.Error())
+	}
+	for i, c := range fakeColumns {
+		if cd := tbl.ColDefs[i]; cd.CellType != want[c.dbType] {
+			t.Errorf("sql_test: Expected cell type %d for %s, found %d\n", want[c.dbType], c.dbType, cd.CellType)
+		}
+	}
+	var col int
+	for col < len(fakeColumns)-1 && fakeColumns[col].dbType != "INTERVAL" {
+		col++
+	}
+	if cd, c := tbl.ColDefs[col], tbl.Get(0, col); cd.Justify != COLJUSTIFYLEFT || c.Type != CELLSTRING || c.Sval != "1 day" {
+		t.Errorf("sql_test: Expected interval as left justified string, found %#v\n", c)
+	}
+}
+
+func TestFromSQLRowsStream(t *testing.T) {
+	// csv rows are written as read, same as csv output of the whole table
+	rows := fakeQuery(t)
+	full, err := FromSQLRows(rows)
+	if err != nil {
+		t.Fatalf("sql_test: Error creating table: %s\n", err.Error())
+	}
+	rows.Close()
+	var want bytes.Buffer
+	full.CSVprintTable(&want)
+
+	var temp bytes.Buffer
+	rows = fakeQuery(t)
+	tbl, err := FromSQLRows(rows, SQLStream{W: &temp, Format: FORMATCSV})
+	if err != nil {
+		t.Fatalf("sql_test: Error streaming csv: %s\n", err.Error())
+	}
+	rows.Close()
+	if tbl.RowCount() != 0 || tbl.ColCount() != 6 {
+		t.Errorf("sql_test: Expected table without rows, found %d rows\n", tbl.RowCount())
+	}
+	if temp.String() != want.String() {
+		t.Errorf("sql_test: Expected streamed csv:\n%s\nbut found:\n%s\n", want.String(), temp.String())
+	}
+
+	// json rows
+	temp.Reset()
+	rows = fakeQuery(t)
+	if _, err := FromSQLRows(rows, SQLStream{W: &temp, Format: FORMATJSON, Layout: JSONROWARRAY}); err != nil {
+		t.Fatalf("sql_test: Error streaming json: %s\n", err.Error())
+	}
+	rows.Close()
+	var out struct {
+		Columns []map[string]interface{} `json:"columns"`
+		Rows    [][]interface{}          `json:"rows"`
+	}
+	if err := json.Unmarshal(temp.Bytes(), &out); err != nil {
+		t.Fatalf("sql_test: Error decoding streamed json: %s\n%s\n", err.Error(), temp.String())
+	}
+	if len(out.Columns) != 6 || len(out.Rows) != 3 || out.Rows[0][1] != "acme" || out.Rows[1][1] != nil {
+		t.Errorf("sql_test: Unexpected streamed json:\n%s\n", temp.String())
+	}
+
+	rows = fakeQuery(t)
+	defer rows.Close()
+	if _, err := FromSQLRows(rows, SQLStream{W: &temp, Format: FORMATPDF}); err == nil || !strings.Contains(err.Error(), "cannot be streamed") {
+		t.Errorf("sql_test: Expected error streaming pdf, found %v\n", err)
+	}
+	if _, err := FromSQLRows(rows, 1); err == nil {
+		t.Errorf("sql_test: Expected error for unsupported option\n")
+	}
+}
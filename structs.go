@@ -0,0 +1,407 @@
+package gotable
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structTag is the key of the struct tags read by FromStructs, e.g.
+//
+//	Amount float64 `gotable:"title=Amount,width=12,justify=right,format=%.2f"`
+//
+// title, width and justify (left or right) define the column. format is a
+// printf format of an int or float field, a single verb such as %.2f or %x, it
+// becomes the Pfmt of the column, padded to its width unless it has a width of
+// its own. datetime makes a time.Time a CELLDATETIME rather than CELLDATE
+// column. omit or "-" leaves the field out.
+const structTag = "gotable"
+
+// structFormat matches the format option of a struct tag, the groups are the
+// flags, width, precision and verb
+var structFormat = regexp.MustCompile(`^%([-+# 0]*)([0-9]*)(\.[0-9]+)?([a-zA-Z])$`)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structField describes the column of an exported struct field
+type structField struct {
+	index    int    // field index in the struct
+	name     string // field name, it is the key of the column
+	title    string
+	width    int // 0 for the default width
+	justify  int
+	celltype int
+	format   string
+	stringer bool // value is set by String()
+}
+
+// getStructFields returns the columns of the exported fields of struct type st
+// which can be put in a cell: ints, floats, strings, bools, time.Time,
+// fmt.Stringers and pointers to them
+func getStructFields(st reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		sf := structField{index: i, name: f.Name, title: f.Name, justify: COLJUSTIFYLEFT}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft == timeType:
+			sf.celltype = CELLDATE
+		case ft.Implements(stringerType) || reflect.PtrTo(ft).Implements(stringerType):
+			sf.celltype = CELLSTRING
+			sf.stringer = !reflect.PtrTo(ft).Implements(textUnmarshalerType)
+		default:
+			switch ft.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				sf.celltype, sf.justify = CELLINT, COLJUSTIFYRIGHT
+			case reflect.Float32, reflect.Float64:
+				sf.celltype, sf.justify = CELLFLOAT, COLJUSTIFYRIGHT
+			case reflect.String, reflect.Bool:
+				sf.celltype = CELLSTRING
+			default:
+				continue // no cell for it
+			}
+		}
+
+		tag := f.Tag.Get(structTag)
+		if tag == "-" {
+			continue
+		}
+		omit := false
+		for _, opt := range strings.Split(tag, ",") {
+			opt = strings.TrimSpace(opt)
+			name, value := opt, ""
+			if i := strings.Index(opt, "="); i >= 0 {
+				name, value = opt[:i], opt[i+1:]
+			}
+			switch name {
+			case "":
+			case "omit":
+				omit = true
+			case "title":
+				sf.title = value
+			case "width":
+				w, err := strconv.Atoi(value)
+				if err != nil || w < 1 {
+					return nil, fmt.Errorf("Invalid width in tag of field %s: %s", f.Name, value)
+				}
+				sf.width = w
+			case "justify":
+				switch value {
+				case "left":
+					sf.justify = COLJUSTIFYLEFT
+				case "right":
+					sf.justify = COLJUSTIFYRIGHT
+				default:
+					return nil, fmt.Errorf("Invalid justify in tag of field %s: %s", f.Name, value)
+				}
+			case "format":
+				m := structFormat.FindStringSubmatch(value)
+				verbs := map[int]string{CELLINT: "bdoxX", CELLFLOAT: "eEfFgG"}[sf.celltype]
+				if m == nil || !strings.Contains(verbs, m[4]) {
+					return nil, fmt.Errorf("Invalid format in tag of field %s: %s", f.Name, value)
+				}
+				sf.format = value
+			case "datetime":
+				if sf.celltype != CELLDATE {
+					return nil, fmt.Errorf("Option datetime in tag of field %s needs a time.Time", f.Name)
+				}
+				sf.celltype = CELLDATETIME
+			default:
+				return nil, fmt.Errorf("Unknown option in tag of field %s: %s", f.Name, opt)
+			}
+		}
+		if !omit {
+			fields = append(fields, sf)
+		}
+	}
+	return fields, nil
+}
+
+// structType returns the struct type of the elements of slice type t, the
+// elements are structs or pointers to structs
+func structType(t reflect.Type) (reflect.Type, error) {
+	if t.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Expected a slice of structs, found %s", t)
+	}
+	st := t.Elem()
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Expected a slice of structs, found %s", t)
+	}
+	return st, nil
+}
+
+// FromStructs returns a new table with a column for each exported field of the
+// structs in slice, a slice of structs or pointers to structs, and a row for
+// each struct. Columns are described by struct tags, see structTag, by default
+// the title of a column is the name of the field, and the name is its key.
+// Ints, floats and strings are put in CELLINT, CELLFLOAT and CELLSTRING cells,
+// time.Time in CELLDATE cells, bools and fmt.Stringers in CELLSTRING cells.
+// Nil pointers leave the cell empty. Fields of other types are left out. The
+// only option is a string, the title of the table. It returns an error for
+// unsigned values which don't fit in an int64.
+func FromStructs(slice interface{}, opts ...interface{}) (*Table, error) {
+	var t Table
+	t.Init()
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case string:
+			t.SetTitle(v)
+		default:
+			return nil, fmt.Errorf("Unsupported option %T for FromStructs", opt)
+		}
+	}
+
+	sv := reflect.ValueOf(slice)
+	if !sv.IsValid() {
+		return nil, fmt.Errorf("Expected a slice of structs, found nil")
+	}
+	st, err := structType(sv.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := getStructFields(st)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.width == 0 {
+			f.width = 10
+			if f.celltype == CELLDATETIME {
+				f.width = len(t.DateTimeFmt)
+			}
+		}
+		t.AddColumn(f.title, f.width, f.celltype, f.justify)
+		cd := &t.ColDefs[len(t.ColDefs)-1]
+		cd.Key = f.name
+		if f.format != "" {
+			cd.Pfmt = f.format
+			if m := structFormat.FindStringSubmatch(f.format); m[2] == "" {
+				flags := m[1]
+				if f.justify == COLJUSTIFYLEFT && !strings.Contains(flags, "-") {
+					flags += "-"
+				}
+				cd.Pfmt = fmt.Sprintf("%%%s%d%s%s", flags, cd.Width, m[3], m[4])
+			}
+		}
+	}
+
+	for i := 0; i < sv.Len(); i++ {
+		t.AddRow()
+		v := reflect.Indirect(sv.Index(i))
+		if !v.IsValid() { // nil pointer
+			continue
+		}
+		for col, f := range fields {
+			if err := t.putStructField(col, f, v.Field(f.index)); err != nil {
+				return nil, fmt.Errorf("Cannot put field %s of element %d: %s", f.name, i, err.Error())
+			}
+		}
+	}
+	return &t, nil
+}
+
+// putStructField puts the value of a struct field in column col of the last row
+func (t *Table) putStructField(col int, f structField, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == timeType:
+		if f.celltype == CELLDATETIME {
+			t.Putdt(-1, col, v.Interface().(time.Time))
+		} else {
+			t.Putd(-1, col, v.Interface().(time.Time))
+		}
+		return nil
+	case v.Type().Implements(stringerType):
+		t.Puts(-1, col, v.Interface().(fmt.Stringer).String())
+		return nil
+	case v.CanAddr() && v.Addr().Type().Implements(stringerType):
+		t.Puts(-1, col, v.Addr().Interface().(fmt.Stringer).String())
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		t.Puti(-1, col, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("Value %d overflows int64", v.Uint())
+		}
+		t.Puti(-1, col, int64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		t.Putf(-1, col, v.Float())
+	case reflect.Bool:
+		t.Puts(-1, col, strconv.FormatBool(v.Bool()))
+	case reflect.String:
+		t.Puts(-1, col, v.String())
+	}
+	return nil
+}
+
+// ToStructs sets out, a pointer to a slice of structs or pointers to structs,
+// to a struct for each row of the table. A field is set from the column whose
+// key is the name of the field or, if there is none, whose title is the title
+// of the field, see FromStructs. Fields without a column are left zero, just
+// like fields of empty cells, nil for pointers. Cells are converted to the type
+// of the field if possible, e.g. strings are parsed as numbers. fmt.Stringers
+// are set only if they implement encoding.TextUnmarshaler too.
+func (t *Table) ToStructs(out interface{}) error {
+	pv := reflect.ValueOf(out)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() {
+		return fmt.Errorf("Expected a pointer to a slice of structs, found %T", out)
+	}
+	sv := pv.Elem()
+	st, err := structType(sv.Type())
+	if err != nil {
+		return err
+	}
+
+	fields, err := getStructFields(st)
+	if err != nil {
+		return err
+	}
+	cols := map[int]structField{}
+	for _, f := range fields {
+		if f.stringer {
+			continue // no way back from String()
+		}
+		if col, err := t.ColumnIndex(f.name); err == nil {
+			cols[col] = f
+			continue
+		}
+		for col := range t.ColDefs {
+			if t.ColDefs[col].ColTitle == f.title {
+				cols[col] = f
+				break
+			}
+		}
+	}
+
+	s := reflect.MakeSlice(sv.Type(), len(t.Row), len(t.Row))
+	for row := range t.Row {
+		v := s.Index(row)
+		if v.Kind() == reflect.Ptr {
+			v.Set(reflect.New(st))
+			v = v.Elem()
+		}
+		for col, f := range cols {
+			if err := t.setStructField(v.Field(f.index), t.Row[row].Col[col], col); err != nil {
+				return fmt.Errorf("Cannot set field %s from row %d, column %d: %s", f.name, row, col, err.Error())
+			}
+		}
+	}
+	sv.Set(s)
+	return nil
+}
+
+// setStructField sets struct field v to the value of cell c of column col
+func (t *Table) setStructField(v reflect.Value, c Cell, col int) error {
+	if c.Type == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := t.setStructField(p.Elem(), c, col); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+
+	s := strings.TrimSpace(t.cellText(c, col))
+	number := strings.Replace(s, ",", "", -1) // humanized numbers
+	if v.Type() == timeType {
+		if !c.isDate() {
+			d, err := time.Parse(t.DateFmt, s)
+			if err != nil {
+				if d, err = time.Parse(t.DateTimeFmt, s); err != nil {
+					return err
+				}
+			}
+			c.Dval = d
+		}
+		v.Set(reflect.ValueOf(c.Dval))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch c.Type {
+		case CELLINT:
+			v.SetInt(c.Ival)
+		case CELLFLOAT:
+			v.SetInt(int64(c.Fval))
+		default:
+			i, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return err
+			}
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case c.isNumber() && c.float() < 0:
+			return fmt.Errorf("Negative value %s for unsigned field", s)
+		case c.Type == CELLINT:
+			v.SetUint(uint64(c.Ival))
+		case c.Type == CELLFLOAT:
+			v.SetUint(uint64(c.Fval))
+		default:
+			i, err := strconv.ParseUint(number, 10, 64)
+			if err != nil {
+				return err
+			}
+			v.SetUint(i)
+		}
+	case reflect.Float32, reflect.Float64:
+		switch c.Type {
+		case CELLINT, CELLFLOAT:
+			v.SetFloat(c.float())
+		default:
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(f)
+		}
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.String:
+		if c.Type == CELLSTRING {
+			v.SetString(c.Sval)
+		} else {
+			v.SetString(s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package gotable
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type invoiceStatus int
+
+func (s invoiceStatus) String() string { return []string{"open", "paid"}[s] }
+
+type invoiceKind string
+
+func (k invoiceKind) String() string { return strings.ToUpper(string(k)) }
+func (k *invoiceKind) UnmarshalText(b []byte) error {
+	*k = invoiceKind(strings.ToLower(string(b)))
+	return nil
+}
+
+type invoice struct {
+	Number   int       `gotable:"title=No,width=4"`
+	Customer string    `gotable:"width=12"`
+	Amount   float64   `gotable:"title=Amount,width=12,justify=right,format=%.3f"`
+	Tax      *float64  `gotable:"justify=left"`
+	Due      time.Time `gotable:"title=Due Date"`
+	Sent     time.Time `gotable:"datetime"`
+	Paid     bool
+	Status   invoiceStatus
+	Kind     invoiceKind
+	Internal string `gotable:"omit"`
+	Skipped  string `gotable:"-"`
+	Count    uint16 `gotable:"format=%04x"`
+	Lines    []string
+	note     string
+}
+
+func TestFromStructs(t *testing.T) {
+	tax := 1.5
+	due := time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC)
+	in := []invoice{
+		{Number: 1, Customer: "acme", Amount: 12.5, Tax: &tax, Due: due, Sent: due.Add(time.Hour), Paid: true, Status: 1, Kind: "retail", Internal: "x", note: "y"},
+		{Number: 2, Customer: "globex", Amount: 1000, Due: due.AddDate(0, 1, 0), Count: 255},
+	}
+	tbl, err := FromStructs(in, "Invoices")
+	if err != nil {
+		t.Fatalf("structs_test: Error creating table: %s\n", err.Error())
+	}
+
+	var titles []string
+	for _, cd := range tbl.ColDefs {
+		titles = append(titles, cd.ColTitle)
+	}
+	if s := strings.Join(titles, ","); s != "No,Customer,Amount,Tax,Due Date,Sent,Paid,Status,Kind,Count" {
+		t.Errorf("structs_test: Unexpected columns: %s\n", s)
+	}
+	for _, c := range []struct {
+		col, celltype, justify, width int
+	}{{0, CELLINT, COLJUSTIFYRIGHT, 4}, {2, CELLFLOAT, COLJUSTIFYRIGHT, 12}, {3, CELLFLOAT, COLJUSTIFYLEFT, 10}, {4, CELLDATE, COLJUSTIFYLEFT, 10}, {5, CELLDATETIME, COLJUSTIFYLEFT, 23}} {
+		cd := tbl.ColDefs[c.col]
+		if cd.CellType != c.celltype || cd.Justify != c.justify || cd.Width != c.width {
+			t.Errorf("structs_test: Unexpected definition of column %d: %#v\n", c.col, cd)
+		}
+	}
+	if tbl.Title != "Invoices" || tbl.RowCount() != 2 || tbl.Getf(0, 2) != 12.5 || tbl.Getf(0, 3) != 1.5 ||
+		tbl.Get(1, 3).Type != 0 || tbl.Gets(0, 6) != "true" || tbl.Gets(0, 7) != "paid" || tbl.Gets(0, 8) != "RETAIL" {
+		t.Errorf("structs_test: Unexpected rows: %v\n", tbl.Row)
+	}
+	if v, err := tbl.GetsByKey(1, "Customer"); err != nil || v != "globex" {
+		t.Errorf("structs_test: Expected field name as column key, found %q\n", v)
+	}
+	var temp bytes.Buffer
+	if err := tbl.TextprintTable(&temp); err != nil || !strings.Contains(temp.String(), "    1000.000  ") || !strings.Contains(temp.String(), "      00ff") {
+		t.Errorf("structs_test: Unexpected text output:\n%s\n", temp.String())
+	}
+
+	// back to structs, fields which can't be set stay zero
+	var out []*invoice
+	if err := tbl.ToStructs(&out); err != nil {
+		t.Fatalf("structs_test: Error creating structs: %s\n", err.Error())
+	}
+	want := in[0]
+	want.Status, want.Internal, want.note = 0, "", ""
+	if len(out) != 2 || !reflect.DeepEqual(*out[0], want) || out[1].Tax != nil || out[1].Amount != 1000 {
+		t.Errorf("structs_test: Expected %#v, found %#v\n", want, out[0])
+	}
+
+	// columns are found by title if they have no key
+	tbl.SetColumnKey(0, "")
+	var out2 []invoice
+	if err := tbl.ToStructs(&out2); err != nil || out2[1].Number != 2 {
+		t.Errorf("structs_test: Expected number by title, found %v\n", out2)
+	}
+
+	for _, bad := range []interface{}{nil, in[0], []int{1}, []struct {
+		A int `gotable:"width=x"`
+	}{{1}}, []struct {
+		A int `gotable:"datetime"`
+	}{{1}}, []struct {
+		A int `gotable:"format=%.2f"`
+	}{{1}}, []struct {
+		A string `gotable:"format=%s"`
+	}{{"a"}}, []struct {
+		A uint64
+	}{{math.MaxUint64}}} {
+		if _, err := FromStructs(bad); err == nil {
+			t.Errorf("structs_test: Expected error for %#v\n", bad)
+		}
+	}
+	if _, err := FromStructs(in, 1); err == nil {
+		t.Errorf("structs_test: Expected error for unsupported option\n")
+	}
+	if err := tbl.ToStructs(out2); err == nil {
+		t.Errorf("structs_test: Expected error for non pointer\n")
+	}
+	var counts []struct{ Count uint }
+	tbl.Puti(0, 9, -1)
+	if err := tbl.ToStructs(&counts); err == nil {
+		t.Errorf("structs_test: Expected error for negative unsigned value\n")
+	}
+	tbl.Putf(0, 9, -0.5)
+	if err := tbl.ToStructs(&counts); err == nil {
+		t.Errorf("structs_test: Expected error for negative unsigned value\n")
+	}
+	tbl.Puts(0, 6, "maybe")
+	if err := tbl.ToStructs(&out2); err == nil {
+		t.Errorf("structs_test: Expected error for invalid bool\n")
+	}
+}
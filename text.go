@@ -5,8 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
-
-	"github.com/dustin/go-humanize"
+	"strings"
 )
 
 // TextTable struct used to prepare table in text version
@@ -39,7 +38,12 @@ func (tt *TextTable) writeTableOutput(w io.Writer) error {
 
 		// append rows
 		if rowsStr, err := tt.getRows(); err != nil {
-			tableOut += stringln(err.Error())
+			// footer, e.g. a count of 0, is shown under the headers without rows
+			if len(tt.Table.Footer) > 0 {
+				tableOut += headerStr + stringln(err.Error()) + tt.getFooter()
+			} else {
+				tableOut += stringln(err.Error())
+			}
 		} else {
 			// if rows exist, then only show headers
 			tableOut += headerStr
@@ -123,6 +127,11 @@ func (tt *TextTable) getHeaders() (string, error) {
 
 	s := ""
 
+	// header groups are stacked above the column headers
+	for _, groups := range tt.Table.getHeaderGroupRows() {
+		s += tt.sprintHeaderGroups(groups)
+	}
+
 	for j := 0; j < len(tt.Table.ColDefs[0].Hdr); j++ {
 		for i := 0; i < len(tt.Table.ColDefs); i++ {
 			sf := ""
@@ -160,7 +169,23 @@ func (tt *TextTable) getRows() (string, error) {
 		rowsStr += s
 	}
 
-	return rowsStr, nil
+	return rowsStr + tt.getFooter(), nil
+}
+
+// getFooter returns the footer rows after a separator line
+func (tt *TextTable) getFooter() string {
+	if len(tt.Table.Footer) == 0 {
+		return ""
+	}
+	var s string
+	if !tt.Table.hasLineAfter(tt.Table.RowCount() - 1) {
+		s += tt.sprintLineText()
+	}
+	for i := 0; i < len(tt.Table.Footer); i++ {
+		c := tt.Table.getFooterRow(i)
+		s += tt.sprintColset(-1, &c)
+	}
+	return s
 }
 
 func (tt *TextTable) getRow(row int) (string, error) {
@@ -189,110 +214,142 @@ func (tt *TextTable) getRow(row int) (string, error) {
 		}
 	}
 
-	rowColumns := tt.Table.ColCount()
+	s += tt.sprintColset(row, &tt.Table.Row[row])
 
-	// columns string chunk map, each column holds list of string
-	// used for multi line text
-	colMultiLineTextMap := map[int][]string{}
-
-	// get Height of row that require to fit the content of max cell string content
-	// by default table has no all the data in string format, so that we need to add
-	// logic here only, to support multi line functionality
-	for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
-		if tt.Table.Row[row].Col[gridColIndex].Type == CELLSTRING {
-			cd := tt.Table.ColDefs[gridColIndex]
+	if len(tt.Table.LineAfter) > 0 {
+		j := sort.SearchInts(tt.Table.LineAfter, row)
+		if j < len(tt.Table.LineAfter) && row == tt.Table.LineAfter[j] {
+			s += tt.sprintLineText()
+		}
+	}
+	return s, nil
+}
 
-			// get multi line text
-			a, _ := getMultiLineText(tt.Table.Row[row].Col[gridColIndex].Sval, cd.Width)
+// sprintColset formats the cells of a row, row is the index of the row in the
+// table, it is used to find merged cells
+func (tt *TextTable) sprintColset(row int, cs *Colset) string {
+	var s string
+	rowColumns := tt.Table.ColCount()
 
-			// store multi line text list in column multi line text map
-			colMultiLineTextMap[gridColIndex] = a
+	// lines of each cell of the row, used for multi line text. A merged cell
+	// takes the width of all of its columns and the spaces between them
+	var cellLines [][]string
+	var cellWidths []int
+	for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
+		cd := tt.Table.ColDefs[gridColIndex]
+		c := cs.Col[gridColIndex]
 
-			// if greater value found then store it
-			if len(a) > tt.Table.Row[row].Height {
-				tt.Table.Row[row].Height = len(a)
+		if m := tt.Table.getMerge(row, gridColIndex); m != nil {
+			for j := 1; j < m.ColSpan; j++ {
+				cd.Width += tt.TextColSpace + tt.Table.ColDefs[gridColIndex+j].Width
+			}
+			tt.Table.AdjustFormatString(&cd)
+			// value is shown in the top row of the merge only
+			if m.Row != row {
+				c = Cell{}
 			}
+			gridColIndex += m.ColSpan - 1
 		}
-	}
 
-	rowHeight := tt.Table.Row[row].Height
+		a := tt.getCellLines(c, cd)
 
-	// rowGrid holds grid for row with multi line text
-	// NOTE: Non constant bound array error
-	// cannot create with runtime variable value
-	rowGrid := [][]string{}
+		// get Height of row that require to fit the content of max cell string content
+		if len(a) > cs.Height {
+			cs.Height = len(a)
+		}
+		cellLines = append(cellLines, a)
+		cellWidths = append(cellWidths, cd.Width)
+	}
 
-	// fill grid with empty whitespace value so that it can hold proper spacing
-	// to fit the row in table text output
-	emptyCols := make([]string, rowColumns)
-	for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
-		// assign default string with length of column width
-		emptyCols[gridColIndex] = mkstr(tt.Table.ColDefs[gridColIndex].Width, ' ')
+	rowHeight := cs.Height
+	if rowHeight < 1 {
+		rowHeight = 1
 	}
-	// fit these prepared empty column list in rowGrid for each row
+
 	for gridRowIndex := 0; gridRowIndex < rowHeight; gridRowIndex++ {
-		rowGrid = append(rowGrid, emptyCols)
+		var line []string
+		for i, a := range cellLines {
+			if gridRowIndex < len(a) {
+				line = append(line, a[gridRowIndex])
+			} else {
+				// fill with empty whitespace so that it holds proper spacing
+				line = append(line, mkstr(cellWidths[i], ' '))
+			}
+		}
+		// separate text columns and append new line
+		s += stringln(strings.Join(line, mkstr(tt.TextColSpace, ' ')))
 	}
+	return s
+}
 
-	// for the first line in grid fill all type of data in it
-	// for string type take it from col multi line text map first chunk
-	// FIRST LINE OF ROW GRID
-	for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
-		switch tt.Table.Row[row].Col[gridColIndex].Type {
-		case CELLFLOAT:
-			s += fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, humanize.FormatFloat("#,###.##", tt.Table.Row[row].Col[gridColIndex].Fval))
-		case CELLINT:
-			s += fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, tt.Table.Row[row].Col[gridColIndex].Ival)
-		case CELLSTRING:
-			s += fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, colMultiLineTextMap[gridColIndex][0])
-		case CELLDATE:
-			s += fmt.Sprintf("%*.*s", tt.Table.ColDefs[gridColIndex].Width, tt.Table.ColDefs[gridColIndex].Width, tt.Table.Row[row].Col[gridColIndex].Dval.Format(tt.Table.DateFmt))
-		case CELLDATETIME:
-			s += fmt.Sprintf("%*.*s", tt.Table.ColDefs[gridColIndex].Width, tt.Table.ColDefs[gridColIndex].Width, tt.Table.Row[row].Col[gridColIndex].Dval.Format(tt.Table.DateTimeFmt))
-		default:
-			s += mkstr(tt.Table.ColDefs[gridColIndex].Width, ' ')
+// getCellLines returns the lines of a cell formatted in the width of cd,
+// only strings are wrapped on multiple lines
+func (tt *TextTable) getCellLines(c Cell, cd ColumnDef) []string {
+	switch c.Type {
+	case CELLFLOAT:
+		return []string{cd.floatText(c.Fval)}
+	case CELLINT:
+		return []string{fmt.Sprintf(cd.Pfmt, c.Ival)}
+	case CELLSTRING:
+		a, _ := getMultiLineText(c.Sval, cd.Width)
+		for i := 0; i < len(a); i++ {
+			a[i] = fmt.Sprintf(cd.Pfmt, a[i])
 		}
-		// append text col whitespace
-		s += mkstr(tt.TextColSpace, ' ')
+		return a
+	case CELLDATE:
+		return []string{fmt.Sprintf("%*.*s", cd.Width, cd.Width, c.Dval.Format(tt.Table.DateFmt))}
+	case CELLDATETIME:
+		return []string{fmt.Sprintf("%*.*s", cd.Width, cd.Width, c.Dval.Format(tt.Table.DateTimeFmt))}
 	}
-	// remove last textColSpace from s
-	s = s[0 : len(s)-tt.TextColSpace]
-	// append new line after first line of grid
-	s = stringln(s)
-
-	// now proceed with rest of the line in row grid
-	// for multi line text
-	for gridRowIndex := 1; gridRowIndex < rowHeight; gridRowIndex++ {
+	return []string{mkstr(cd.Width, ' ')}
+}
 
-		for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
+// sprintHeaderGroups returns a row of header groups, titles are centered over
+// their columns and underlined
+func (tt *TextTable) sprintHeaderGroups(groups []HeaderGroup) string {
+	var titles [][]string
+	var widths []int
+	height := 1
+	for _, g := range groups {
+		w := tt.Table.ColDefs[g.FromCol].Width
+		for i := g.FromCol + 1; i <= g.ToCol; i++ {
+			w += tt.TextColSpace + tt.Table.ColDefs[i].Width
+		}
+		var a []string
+		if g.Title != "" {
+			a, _ = getMultiLineText(g.Title, w)
+		}
+		if len(a) > height {
+			height = len(a)
+		}
+		titles = append(titles, a)
+		widths = append(widths, w)
+	}
 
-			if tt.Table.Row[row].Col[gridColIndex].Type == CELLSTRING {
-				if gridRowIndex >= len(colMultiLineTextMap[gridColIndex]) {
-					rowGrid[gridRowIndex][gridColIndex] = fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, "")
-				} else {
-					rowGrid[gridRowIndex][gridColIndex] = fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, colMultiLineTextMap[gridColIndex][gridRowIndex])
-				}
+	var s string
+	for j := 0; j < height; j++ {
+		var line []string
+		for i, a := range titles {
+			// titles are aligned to the bottom like the column headers
+			k := j - (height - len(a))
+			if k < 0 {
+				line = append(line, mkstr(widths[i], ' '))
+			} else {
+				line = append(line, centerstr(a[k], widths[i]))
 			}
-
-			s += rowGrid[gridRowIndex][gridColIndex]
-
-			// append text col whitespace
-			s += mkstr(tt.TextColSpace, ' ')
 		}
-
-		// remove last textColSpace from s
-		s = s[0 : len(s)-tt.TextColSpace]
-		// append new line
-		s = stringln(s)
+		s += stringln(strings.Join(line, mkstr(tt.TextColSpace, ' ')))
 	}
 
-	if len(tt.Table.LineAfter) > 0 {
-		j := sort.SearchInts(tt.Table.LineAfter, row)
-		if j < len(tt.Table.LineAfter) && row == tt.Table.LineAfter[j] {
-			s += tt.sprintLineText()
+	var line []string
+	for i, a := range titles {
+		c := byte(' ')
+		if len(a) > 0 {
+			c = '-'
 		}
+		line = append(line, mkstr(widths[i], c))
 	}
-	return s, nil
+	return s + stringln(strings.Join(line, mkstr(tt.TextColSpace, ' ')))
 }
 
 // SprintLineText returns a line across all rows in the table
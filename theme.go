@@ -0,0 +1,179 @@
+package gotable
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// THEMEPLAIN et. al. are the names of bundled themes
+const (
+	THEMEPLAIN   = "plain"
+	THEMESTRIPED = "striped"
+	THEMECOMPACT = "compact"
+	THEMEDARK    = "dark"
+	THEMEPRINT   = "print"
+)
+
+// Theme holds the look of html and pdf output. It is applied over the default
+// css, blank fields keep the default look. Colors must be css hex colors,
+// e.g. #FFF or #1E1E1E, so that the native pdf engine can use them too.
+type Theme struct {
+	Name string
+
+	FontFamily string // css font-family, e.g. `Courier,monospace`
+	FontSize   string // css font-size in px, e.g. `12px`
+	Color      string // text color
+	Background string // background color
+
+	HeaderColor      string // text color of column headers
+	HeaderBackground string // background color of column headers
+	HeaderBorder     string // css border below column headers, e.g. `2px solid #BBB`
+
+	StripeBackground string // background color of every other row, blank for no striping
+	LineBorder       string // css border of top-line and bottom-line rows, e.g. `1px solid #BBB`
+	CellPadding      string // css padding of cells, html only, e.g. `5px 10px`
+}
+
+// themes holds the bundled themes
+var themes = map[string]Theme{
+	// default look
+	THEMEPLAIN: {Name: THEMEPLAIN},
+
+	THEMESTRIPED: {
+		Name:             THEMESTRIPED,
+		HeaderBackground: "#E6E6E6",
+		StripeBackground: "#F4F4F4",
+	},
+
+	THEMECOMPACT: {
+		Name:        THEMECOMPACT,
+		FontSize:    "11px",
+		CellPadding: "1px 4px",
+	},
+
+	THEMEDARK: {
+		Name:             THEMEDARK,
+		Color:            "#E0E0E0",
+		Background:       "#1E1E1E",
+		HeaderColor:      "#FFFFFF",
+		HeaderBackground: "#333333",
+		HeaderBorder:     "2px solid #666666",
+		StripeBackground: "#282828",
+		LineBorder:       "1px solid #666666",
+	},
+
+	// black on white, no backgrounds to save ink
+	THEMEPRINT: {
+		Name:         THEMEPRINT,
+		FontFamily:   "Courier,monospace",
+		Color:        "#000000",
+		Background:   "#FFFFFF",
+		HeaderColor:  "#000000",
+		HeaderBorder: "2px solid #000000",
+		LineBorder:   "1px solid #000000",
+	},
+}
+
+// Themes returns a sorted list of the names of bundled themes
+func Themes() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTheme returns a copy of the bundled theme with the given name, it can be
+// customized before passing it to Table.SetTheme
+func GetTheme(name string) (Theme, error) {
+	theme, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("Unknown theme: %s", name)
+	}
+	return theme, nil
+}
+
+// SetTheme sets the theme of html and pdf output
+func (t *Table) SetTheme(theme Theme) {
+	t.theme = &theme
+}
+
+// GetTheme returns the theme of the table, nil if it is not set
+func (t *Table) GetTheme() *Theme {
+	return t.theme
+}
+
+// getCSS returns the css rules of the theme for the table container selector
+func (th *Theme) getCSS(container string) string {
+	var css string
+	rule := func(selector string, props ...string) {
+		var s string
+		for i := 0; i < len(props); i += 2 {
+			if props[i+1] != "" {
+				s += props[i] + `:` + props[i+1] + `;`
+			}
+		}
+		if s != "" {
+			css += selector + `{` + s + `}`
+		}
+	}
+
+	rule(container, "font-family", th.FontFamily, "font-size", th.FontSize, "color", th.Color, "background-color", th.Background)
+	rule(container+` table thead tr th`, "color", th.HeaderColor, "background-color", th.HeaderBackground, "border-bottom", th.HeaderBorder)
+	rule(container+` table td,`+container+` table th`, "padding", th.CellPadding)
+	rule(container+` table tbody tr:nth-child(even) td`, "background-color", th.StripeBackground)
+	rule(container+` table tbody tr.top-line td`, "border-top", th.LineBorder)
+	rule(container+` table tbody tr.bottom-line td`, "border-bottom", th.LineBorder)
+	return css
+}
+
+// pdfColor is a color with rgb components in 0..1 range
+type pdfColor [3]float64
+
+// parseCSSColor parses a css hex color, e.g. #FFF or #1E1E1E
+func parseCSSColor(s string) (pdfColor, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return pdfColor{}, false
+	}
+	var c pdfColor
+	for i := 0; i < 3; i++ {
+		v, err := strconv.ParseUint(s[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return pdfColor{}, false
+		}
+		c[i] = float64(v) / 255
+	}
+	return c, true
+}
+
+// parseCSSBorder parses a css border like `1px solid #BBB` into width in
+// points and color
+func parseCSSBorder(s string) (float64, pdfColor, bool) {
+	var width float64
+	var c pdfColor
+	var ok bool
+	for _, f := range strings.Fields(s) {
+		if strings.HasSuffix(f, "px") {
+			if v, err := strconv.ParseFloat(strings.TrimSuffix(f, "px"), 64); err == nil {
+				width = v * 0.75
+				ok = true
+			}
+		} else if strings.HasPrefix(f, "#") {
+			c, _ = parseCSSColor(f)
+		}
+	}
+	return width, c, ok
+}
+
+// parseCSSPixels parses a css size in px, e.g. 12px
+func parseCSSPixels(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "px"), 64)
+	return v, err == nil && v > 0
+}
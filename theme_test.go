@@ -0,0 +1,111 @@
+package gotable
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Theme Table")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 10, CELLINT, COLJUSTIFYRIGHT)
+	for i := 0; i < 4; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "name")
+		tbl.Puti(-1, 1, int64(i))
+	}
+	tbl.AddLineAfter(1)
+
+	if _, err := GetTheme("nosuchtheme"); err == nil {
+		t.Errorf("theme_test: Expected error for unknown theme\n")
+	}
+	if len(Themes()) != 5 {
+		t.Errorf("theme_test: Expected 5 bundled themes, found %v\n", Themes())
+	}
+
+	dark, err := GetTheme(THEMEDARK)
+	if err != nil {
+		t.Fatalf("theme_test: Error getting theme: %s\n", err.Error())
+	}
+	dark.FontFamily = "Courier,monospace"
+	tbl.SetTheme(dark)
+
+	// theme css follows the default css
+	var temp bytes.Buffer
+	if err := tbl.HTMLprintTable(&temp); err != nil {
+		t.Errorf("theme_test: Error creating HTML output: %s\n", err.Error())
+	}
+	s := temp.String()
+	var expected = []string{
+		"div.rpt-table-container{font-family:Courier,monospace;color:#E0E0E0;background-color:#1E1E1E;}",
+		"div.rpt-table-container table thead tr th{color:#FFFFFF;background-color:#333333;border-bottom:2px solid #666666;}",
+		"div.rpt-table-container table tbody tr:nth-child(even) td{background-color:#282828;}",
+		"div.rpt-table-container table tbody tr.bottom-line td{border-bottom:1px solid #666666;}",
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(s, expected[i]) {
+			t.Errorf("theme_test: Expected %q in output, but found:\n%s\n", expected[i], s)
+		}
+	}
+
+	// fragment css is scoped
+	_, css, err := tbl.HTMLFragment("dark")
+	if err != nil {
+		t.Errorf("theme_test: Error creating HTML fragment: %s\n", err.Error())
+	}
+	if !strings.Contains(css, "div#dark.rpt-table-container{font-family:") {
+		t.Errorf("theme_test: Expected scoped theme css, but found:\n%s\n", css)
+	}
+
+	// native pdf uses theme fonts, colors and backgrounds
+	temp.Reset()
+	if err := tbl.PDFprintTable(&temp); err != nil {
+		t.Fatalf("theme_test: Error creating PDF output: %s\n", err.Error())
+	}
+	out := temp.String()
+	var content string
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(out, -1) {
+		zr, err := zlib.NewReader(strings.NewReader(m[1]))
+		if err != nil {
+			t.Fatalf("theme_test: Error inflating page content: %s\n", err.Error())
+		}
+		b, _ := ioutil.ReadAll(zr)
+		content += string(b)
+	}
+	expected = []string{
+		"/BaseFont /Courier ",
+		"q 0.12 0.12 0.12 rg 0 0 ", // page background
+		"q 0.2 0.2 0.2 rg ",        // header background
+		"q 0.16 0.16 0.16 rg ",     // stripe
+		"q 0.88 0.88 0.88 rg BT ",  // text color
+		"q 0.4 0.4 0.4 RG 0.75 w ", // separator line
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(out+content, expected[i]) {
+			t.Errorf("theme_test: Expected %q in pdf\n", expected[i])
+		}
+	}
+	if n := strings.Count(content, "q 0.16 0.16 0.16 rg "); n != 2 {
+		t.Errorf("theme_test: Expected 2 striped rows, found %d\n", n)
+	}
+
+	// theme survives json round-trip
+	b, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("theme_test: Error marshaling table: %s\n", err.Error())
+	}
+	var tbl2 Table
+	if err := json.Unmarshal(b, &tbl2); err != nil {
+		t.Fatalf("theme_test: Error unmarshaling table: %s\n", err.Error())
+	}
+	if tbl2.GetTheme() == nil || *tbl2.GetTheme() != dark {
+		t.Errorf("theme_test: Expected theme to be preserved, found %#v\n", tbl2.GetTheme())
+	}
+}
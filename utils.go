@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"unicode/utf8"
 )
 
 // ==========
@@ -73,6 +74,17 @@ func mkstr(n int, c byte) string {
 	return string(p)
 }
 
+// centerstr returns s centered in a string of n characters, s is cut if it is
+// longer
+func centerstr(s string, n int) string {
+	w := utf8.RuneCountInString(s)
+	if w >= n {
+		return string([]rune(s)[:n])
+	}
+	l := (n - w) / 2
+	return mkstr(l, ' ') + s + mkstr(n-w-l, ' ')
+}
+
 // stringln
 // For text output we want at least one "\n" at the end of a section or title.
 // If the supplied string does not end in "\n", then one will be appended to it
@@ -0,0 +1,128 @@
+package gotable
+
+import (
+	"fmt"
+	"io"
+)
+
+// View is a projection of a table on some of its rows, e.g. the rows of one
+// country. It renders in every export format without copying cells, so changes
+// to the cells of the table show in the view. Rows holds the row indexes of the
+// table in the order they are rendered.
+type View struct {
+	Table *Table
+	Rows  []int
+}
+
+// NewView returns a view of table t on the supplied rows
+func (t *Table) NewView(rows []int) *View {
+	return &View{Table: t, Rows: rows}
+}
+
+// FilterView returns a view of table t on the rows for which pred returns true
+func (t *Table) FilterView(pred func(row int, r Colset) bool) *View {
+	var rows []int
+	for i := range t.Row {
+		if pred(i, t.Row[i]) {
+			rows = append(rows, i)
+		}
+	}
+	return t.NewView(rows)
+}
+
+// Filter returns a new table holding copies of the rows for which pred returns
+// true. The new table gets copies of the column definitions, formats and css
+// of table t, separator lines, rowsets, merges and css of rows follow the rows
+// which are kept. Footer aggregates are computed over the rows of the new table.
+func (t *Table) Filter(pred func(row int, r Colset) bool) *Table {
+	v := t.FilterView(pred)
+	d := t.derive(v.Rows)
+	for i := range d.Row {
+		d.Row[i].Col = append([]Cell(nil), d.Row[i].Col...)
+	}
+	return d
+}
+
+// RowCount returns the number of rows in the view
+func (v *View) RowCount() int {
+	return len(v.Rows)
+}
+
+// Get returns the cell at row,col of the view. If row or col is outside the
+// view's boundaries, then an empty cell is returned.
+func (v *View) Get(row, col int) Cell {
+	if row < 0 || row >= len(v.Rows) {
+		return Cell{}
+	}
+	return v.Table.Get(v.Rows[row], col)
+}
+
+// Export renders the view in the named format, just like Table.Export
+func (v *View) Export(w io.Writer, format string, opts ...interface{}) error {
+	t, err := v.table()
+	if err != nil {
+		return err
+	}
+	return t.Export(w, format, opts...)
+}
+
+// String is the "stringer" method implementation for View, text output
+func (v *View) String() string {
+	t, err := v.table()
+	if err != nil {
+		return err.Error()
+	}
+	return t.String()
+}
+
+// table returns a table sharing the cells of the rows of the view
+func (v *View) table() (*Table, error) {
+	for _, r := range v.Rows {
+		if r < 0 || r >= len(v.Table.Row) {
+			return nil, fmt.Errorf("View row %d is out of range, the table has %d rows", r, len(v.Table.Row))
+		}
+	}
+	return v.Table.derive(v.Rows), nil
+}
+
+// derive returns a copy of table t holding the supplied rows. The rows share
+// their cells with t, everything else which may be changed is copied.
+func (t *Table) derive(rows []int) *Table {
+	d := *t
+	d.ColDefs = make([]ColumnDef, len(t.ColDefs))
+	for i, cd := range t.ColDefs {
+		cd.Hdr = append([]string(nil), cd.Hdr...)
+		cd.Hidden = append([]string(nil), cd.Hidden...)
+		d.ColDefs[i] = cd
+	}
+	if t.CSS != nil {
+		d.CSS = make(map[string]map[string]*CSSProperty, len(t.CSS))
+		for k, props := range t.CSS {
+			d.CSS[k] = make(map[string]*CSSProperty, len(props))
+			for name, p := range props {
+				cp := *p
+				d.CSS[k][name] = &cp
+			}
+		}
+	}
+	d.HeaderGroups = append([]HeaderGroup(nil), t.HeaderGroups...)
+	d.Footer = make([]Colset, len(t.Footer))
+	for i := range t.Footer {
+		d.Footer[i] = Colset{Col: append([]Cell(nil), t.Footer[i].Col...), Height: t.Footer[i].Height}
+	}
+	d.RS = make([]Rowset, len(t.RS))
+	copy(d.RS, t.RS)
+	if t.footerAggs != nil {
+		d.footerAggs = make(map[int]AggFunc, len(t.footerAggs))
+		for col, agg := range t.footerAggs {
+			d.footerAggs[col] = agg
+		}
+	}
+
+	r := make([]Colset, len(rows))
+	for i, j := range rows {
+		r[i] = t.Row[j]
+	}
+	d.reorderRows(r, rows)
+	return &d
+}
@@ -0,0 +1,100 @@
+package gotable
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterView(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("Sales")
+	tbl.AddColumn("Country", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 8, CELLINT, COLJUSTIFYRIGHT)
+	for i, country := range []string{"Norway", "Sweden", "Sweden", "Denmark", "Sweden"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, country)
+		tbl.Puti(-1, 1, int64(i+1))
+	}
+	tbl.AddLineAfter(2)
+	tbl.SetRowCSS(4, []*CSSProperty{{Name: "color", Value: "red"}})
+	tbl.SetFooterAggregate(1, AggSum)
+	sweden := func(row int, r Colset) bool { return r.Col[0].Sval == "Sweden" }
+
+	// filtered table holds copies of the rows
+	f := tbl.Filter(sweden)
+	if f.RowCount() != 3 || f.Geti(2, 1) != 5 || f.GetFooter(0, 1).Ival != 10 {
+		t.Errorf("view_test: Expected 3 rows of Sweden with total 10, found %v\n", f.Row)
+	}
+	if !reflect.DeepEqual(f.LineAfter, []int{1}) {
+		t.Errorf("view_test: Expected line after row 1, found %v\n", f.LineAfter)
+	}
+	if _, ok := f.CSS["row-2"]; !ok {
+		t.Errorf("view_test: Expected row css to move to row 2, found %v\n", f.CSS)
+	}
+	f.Puti(0, 1, 100)
+	f.SetFooterAggregate(1, AggMax)
+	if tbl.Geti(1, 1) != 2 || tbl.GetFooter(0, 1).Ival != 15 || tbl.RowCount() != 5 {
+		t.Errorf("view_test: Expected table to be unchanged by its filtered table\n")
+	}
+
+	// column definitions and css of the filtered table are its own
+	tbl.SetColumnHidden(1, true, FORMATCSV)
+	tbl.SetColCSS(0, []*CSSProperty{{Name: "color", Value: "blue"}})
+	f = tbl.Filter(sweden)
+	f.Puts(0, 0, "Konungariket-Sverige")
+	f.SetColumnKey(0, "country")
+	f.SetColumnHidden(1, true, FORMATHTML)
+	f.SetColCSS(0, []*CSSProperty{{Name: "color", Value: "green"}, {Name: "font-weight", Value: "bold"}})
+	f.CSS[tbl.getCSSMapKeyForCol(0)]["color"].Value = "yellow"
+	if f.ColDefs[0].Width != 20 || f.ColDefs[0].Key != "country" {
+		t.Errorf("view_test: Expected filtered table column 0 to change, found %#v\n", f.ColDefs[0])
+	}
+	cd := tbl.ColDefs[0]
+	if cd.Width != 10 || cd.Key != "" || len(cd.Hdr) != 1 || cd.Hdr[0] != "Country" {
+		t.Errorf("view_test: Expected column 0 to be unchanged by its filtered table, found %#v\n", cd)
+	}
+	if !reflect.DeepEqual(tbl.ColDefs[1].Hidden, []string{FORMATCSV}) {
+		t.Errorf("view_test: Expected column 1 hidden in csv only, found %v\n", tbl.ColDefs[1].Hidden)
+	}
+	if css := tbl.CSS[tbl.getCSSMapKeyForCol(0)]; len(css) != 1 || css["color"].Value != "blue" {
+		t.Errorf("view_test: Expected column css to be unchanged by its filtered table, found %v\n", css)
+	}
+	tbl.SetColumnHidden(1, false, FORMATCSV)
+
+	// view shares the cells of the table
+	v := tbl.FilterView(sweden)
+	if !reflect.DeepEqual(v.Rows, []int{1, 2, 4}) || v.RowCount() != 3 {
+		t.Errorf("view_test: Expected view of rows 1, 2 and 4, found %v\n", v.Rows)
+	}
+	tbl.Puti(4, 1, 50)
+	if v.Get(2, 1).Ival != 50 || v.Get(3, 1).Type != 0 {
+		t.Errorf("view_test: Expected view to show changed cell\n")
+	}
+
+	var temp bytes.Buffer
+	if err := v.Export(&temp, FORMATCSV); err != nil {
+		t.Errorf("view_test: Error creating CSV output: %s\n", err.Error())
+	}
+	expected := "\"Country\",\"Amount\"\n\"Sweden\",       2\n\"Sweden\",       3\n\"Sweden\",      50\n\"#footer\"\n          ,      55\n"
+	if !strings.HasSuffix(temp.String(), expected) {
+		t.Errorf("view_test: Expected csv output to end with:\n%s\nbut found:\n%s\n", expected, temp.String())
+	}
+	for _, format := range Formats() {
+		temp.Reset()
+		if err := v.Export(&temp, format); err != nil {
+			t.Errorf("view_test: Error creating %s output: %s\n", format, err.Error())
+		}
+	}
+	if !strings.Contains(v.String(), "Sweden") || strings.Contains(v.String(), "Norway") {
+		t.Errorf("view_test: Expected text output of Sweden only, found:\n%s\n", v.String())
+	}
+
+	// view of invalid rows cannot be rendered
+	temp.Reset()
+	if err := tbl.NewView([]int{0, 5}).Export(&temp, FORMATTEXT); err == nil {
+		t.Errorf("view_test: Expected error for view of invalid row\n")
+	}
+}
@@ -0,0 +1,521 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSXSHEETNAME et. al. are the constants used in the xlsx version of table object
+const (
+	XLSXSHEETNAME    = "Sheet1"
+	XLSXMAXSHEETNAME = 31 // excel refuses longer sheet names
+
+	// built-in and custom number formats of spreadsheetml
+	xlsxNumFmtGeneral  = 0
+	xlsxNumFmtInt      = 3  // #,##0
+	xlsxNumFmtDate     = 14 // locale dependent short date
+	xlsxNumFmtDateTime = 164
+	xlsxNumFmtFloat    = 165 // first of the formats of floats, one per number of decimals
+
+	// fonts
+	xlsxFontNormal = 0
+	xlsxFontBold   = 1
+	xlsxFontTitle  = 2
+
+	// fills, first two are reserved by excel
+	xlsxFillNone   = 0
+	xlsxFillHeader = 2
+
+	// borders
+	xlsxBorderNone      = 0
+	xlsxBorderTop       = 1
+	xlsxBorderBottom    = 2
+	xlsxBorderTopBottom = 3
+)
+
+// XLSXTable struct used to prepare table in xlsx (Office Open XML workbook) version
+type XLSXTable struct {
+	*Table
+	outbuf   bytes.Buffer
+	rowNum   int               // last written spreadsheet row, 1 based
+	merges   []string          // list of merged ranges, e.g. A1:H1
+	styles   []xlsxStyle       // list of cell formats, index is the style id
+	styleIDs map[xlsxStyle]int // reverse lookup for styles
+	decimals []int             // number of decimals of the formats of floats, index is the format id less xlsxNumFmtFloat
+}
+
+// xlsxStyle holds a single cell format of the workbook
+type xlsxStyle struct {
+	NumFmt, Font, Fill, Border int
+	Align                      string
+}
+
+func (xt *XLSXTable) writeTableOutput(w io.Writer) error {
+	var sheetData string
+
+	// the default style must always be first one
+	xt.styleIDs = map[xlsxStyle]int{}
+	xt.getStyleID(xlsxStyle{})
+
+	// append title
+	sheetData += xt.getTitle()
+
+	// append section 1
+	sheetData += xt.getSection1()
+
+	// append section 2
+	sheetData += xt.getSection2()
+
+	// append section 3
+	sheetData += xt.getSection3()
+
+	// rows above this one are frozen
+	var frozenRows int
+
+	// append headers
+	if headerStr, err := xt.getHeaders(); err != nil {
+		sheetData += xt.getTextRow(err.Error(), xlsxStyle{})
+	} else {
+		sheetData += headerStr
+		frozenRows = xt.rowNum
+
+		// append rows
+		if rowsStr, err := xt.getRows(); err != nil {
+			// footer, e.g. a count of 0, is shown under the headers without rows
+			sheetData += xt.getTextRow(err.Error(), xlsxStyle{}) + xt.getFooter()
+		} else {
+			sheetData += rowsStr
+		}
+	}
+
+	zw := zip.NewWriter(&xt.outbuf)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(xt.getSheetName()))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xt.getStylesXML()},
+		{"xl/worksheets/sheet1.xml", xt.getSheetXML(sheetData, frozenRows)},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(f, xml.Header+p.body); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	// write output to passed io.Writer interface object
+	_, err := w.Write(xt.outbuf.Bytes())
+	return err
+}
+
+func (xt *XLSXTable) getTitle() string {
+	return xt.getMergedRow(xt.Table.GetTitle(), xlsxStyle{Font: xlsxFontTitle, Align: "center"})
+}
+
+func (xt *XLSXTable) getSection1() string {
+	return xt.getMergedRow(xt.Table.GetSection1(), xlsxStyle{Font: xlsxFontBold, Align: "center"})
+}
+
+func (xt *XLSXTable) getSection2() string {
+	return xt.getMergedRow(xt.Table.GetSection2(), xlsxStyle{Align: "center"})
+}
+
+func (xt *XLSXTable) getSection3() string {
+	return xt.getMergedRow(xt.Table.GetSection3(), xlsxStyle{Align: "center"})
+}
+
+// getMergedRow returns a row with the given text merged across all columns
+func (xt *XLSXTable) getMergedRow(s string, style xlsxStyle) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	row := xt.getTextRow(s, style)
+	if xt.Table.ColCount() > 1 {
+		xt.merges = append(xt.merges, xlsxCellRef(0, xt.rowNum)+":"+xlsxCellRef(xt.Table.ColCount()-1, xt.rowNum))
+	}
+	return row
+}
+
+// getTextRow returns a new row with one string cell in first column
+func (xt *XLSXTable) getTextRow(s string, style xlsxStyle) string {
+	xt.rowNum++
+	return `<row r="` + strconv.Itoa(xt.rowNum) + `">` + xt.getStringCell(0, s, style) + `</row>`
+}
+
+func (xt *XLSXTable) getHeaders() (string, error) {
+	// check for blank headers
+	blankHdrsErr := xt.Table.HasHeaders()
+	if blankHdrsErr != nil {
+		return "", blankHdrsErr
+	}
+
+	// header groups are stacked above the column headers as merged cells
+	var tGroups string
+	for _, groups := range xt.Table.getHeaderGroupRows() {
+		tGroups += xt.getHeaderGroupRow(groups)
+	}
+
+	xt.rowNum++
+	var tHeaders string
+	for i := 0; i < len(xt.Table.ColDefs); i++ {
+		style := xlsxStyle{Font: xlsxFontBold, Fill: xlsxFillHeader, Border: xlsxBorderBottom, Align: xlsxAlign(xt.Table.ColDefs[i].Justify)}
+		tHeaders += xt.getStringCell(i, xt.Table.ColDefs[i].ColTitle, style)
+	}
+
+	return tGroups + `<row r="` + strconv.Itoa(xt.rowNum) + `">` + tHeaders + `</row>`, nil
+}
+
+// getHeaderGroupRow returns a header row of header groups
+func (xt *XLSXTable) getHeaderGroupRow(groups []HeaderGroup) string {
+	xt.rowNum++
+	var tRow string
+	for _, g := range groups {
+		style := xlsxStyle{Font: xlsxFontBold, Fill: xlsxFillHeader, Align: "center"}
+		if g.Title != "" {
+			style.Border = xlsxBorderBottom
+		}
+		tRow += xt.getStringCell(g.FromCol, g.Title, style)
+		for i := g.FromCol + 1; i <= g.ToCol; i++ {
+			// merged cells still need the fill and border
+			tRow += `<c r="` + xlsxCellRef(i, xt.rowNum) + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `"/>`
+		}
+		if g.ToCol > g.FromCol {
+			xt.merges = append(xt.merges, xlsxCellRef(g.FromCol, xt.rowNum)+":"+xlsxCellRef(g.ToCol, xt.rowNum))
+		}
+	}
+	return `<row r="` + strconv.Itoa(xt.rowNum) + `">` + tRow + `</row>`
+}
+
+func (xt *XLSXTable) getRows() (string, error) {
+	// check for empty data table
+	blankDataErr := xt.Table.HasData()
+	if blankDataErr != nil {
+		return "", blankDataErr
+	}
+
+	var rowsStr string
+	for i := 0; i < xt.Table.RowCount(); i++ {
+		// for valid row, we will never get an error
+		s, _ := xt.getRow(i)
+		rowsStr += s
+	}
+
+	return rowsStr + xt.getFooter(), nil
+}
+
+// getFooter returns the footer rows, the final rows, in bold below a line
+func (xt *XLSXTable) getFooter() string {
+	var s string
+	for i := 0; i < len(xt.Table.Footer); i++ {
+		xt.rowNum++
+		base := xlsxStyle{Font: xlsxFontBold}
+		if i == 0 {
+			base.Border = xlsxBorderTop
+		}
+		s += xt.getColset(-1, xt.Table.getFooterRow(i), base)
+	}
+	return s
+}
+
+func (xt *XLSXTable) getRow(row int) (string, error) {
+	xt.rowNum++
+
+	// separator lines are drawn as cell borders
+	border := xlsxBorderNone
+	top := xt.Table.hasLineBefore(row)
+	bottom := xt.Table.hasLineAfter(row)
+	switch {
+	case top && bottom:
+		border = xlsxBorderTopBottom
+	case top:
+		border = xlsxBorderTop
+	case bottom:
+		border = xlsxBorderBottom
+	}
+
+	return xt.getColset(row, xt.Table.Row[row], xlsxStyle{Border: border}), nil
+}
+
+// getColset returns the cells of a row in current sheet row, row is the index
+// of the row in the table, it is used to find merged cells. The cells have the
+// font and border of base style.
+func (xt *XLSXTable) getColset(row int, cs Colset, base xlsxStyle) string {
+	border := base.Border
+	var tRow string
+	for i := 0; i < len(cs.Col); i++ {
+		c := cs.Col[i]
+		style := xlsxStyle{Font: base.Font, Border: border, Align: xlsxAlign(xt.Table.ColDefs[i].Justify)}
+		ref := xlsxCellRef(i, xt.rowNum)
+
+		// merged cell holds the value, the cells hidden by it are written empty
+		if m := xt.Table.getMerge(row, i); m != nil {
+			if m.Row == row && m.Col == i {
+				xt.merges = append(xt.merges, ref+":"+xlsxCellRef(m.Col+m.ColSpan-1, xt.rowNum+m.RowSpan-1))
+			} else {
+				c = Cell{}
+			}
+		}
+
+		switch c.Type {
+		case CELLINT:
+			style.NumFmt = xlsxNumFmtInt
+			tRow += `<c r="` + ref + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `"><v>` + strconv.FormatInt(c.Ival, 10) + `</v></c>`
+		case CELLFLOAT:
+			style.NumFmt = xt.getFloatNumFmt(xt.Table.ColDefs[i].Fdecimals)
+			if math.IsNaN(c.Fval) || math.IsInf(c.Fval, 0) {
+				// excel has no representation for NaN and infinity, it is an error value
+				tRow += `<c r="` + ref + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `" t="e"><v>#NUM!</v></c>`
+				break
+			}
+			tRow += `<c r="` + ref + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `"><v>` + strconv.FormatFloat(c.Fval, 'g', -1, 64) + `</v></c>`
+		case CELLSTRING:
+			tRow += xt.getStringCell(i, c.Sval, style)
+		case CELLDATE, CELLDATETIME:
+			serial, ok := xlsxDateSerial(c.Dval)
+			if !ok {
+				// excel can't represent it, write the formatted value instead
+				f := xt.Table.DateFmt
+				if c.Type == CELLDATETIME {
+					f = xt.Table.DateTimeFmt
+				}
+				tRow += xt.getStringCell(i, c.Dval.Format(f), style)
+				break
+			}
+			style.NumFmt = xlsxNumFmtDate
+			if c.Type == CELLDATETIME {
+				style.NumFmt = xlsxNumFmtDateTime
+			}
+			tRow += `<c r="` + ref + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `"><v>` + strconv.FormatFloat(serial, 'f', -1, 64) + `</v></c>`
+		default:
+			// empty cell still needs the border
+			if border != xlsxBorderNone {
+				tRow += `<c r="` + ref + `" s="` + strconv.Itoa(xt.getStyleID(style)) + `"/>`
+			}
+		}
+	}
+
+	return `<row r="` + strconv.Itoa(xt.rowNum) + `">` + tRow + `</row>`
+}
+
+// getStringCell returns an inline string cell in current row
+func (xt *XLSXTable) getStringCell(col int, s string, style xlsxStyle) string {
+	return `<c r="` + xlsxCellRef(col, xt.rowNum) + `" s="` + strconv.Itoa(xt.getStyleID(style)) +
+		`" t="inlineStr"><is><t xml:space="preserve">` + xmlEscape(s) + `</t></is></c>`
+}
+
+// getStyleID returns the index of style in cell formats, style is registered if not exist
+func (xt *XLSXTable) getStyleID(style xlsxStyle) int {
+	if id, ok := xt.styleIDs[style]; ok {
+		return id
+	}
+	xt.styles = append(xt.styles, style)
+	xt.styleIDs[style] = len(xt.styles) - 1
+	return len(xt.styles) - 1
+}
+
+// getFloatNumFmt returns the id of the number format of floats with the
+// supplied number of decimals, it is added to the workbook when first used
+func (xt *XLSXTable) getFloatNumFmt(decimals int) int {
+	if decimals < 0 {
+		decimals = 0
+	}
+	for i, d := range xt.decimals {
+		if d == decimals {
+			return xlsxNumFmtFloat + i
+		}
+	}
+	xt.decimals = append(xt.decimals, decimals)
+	return xlsxNumFmtFloat + len(xt.decimals) - 1
+}
+
+// getSheetName returns a valid sheet name derived from table title
+func (xt *XLSXTable) getSheetName() string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) || r < ' ' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(xt.Table.GetTitle()))
+	if len([]rune(name)) > XLSXMAXSHEETNAME {
+		name = string([]rune(name)[:XLSXMAXSHEETNAME])
+	}
+	if name == "" {
+		name = XLSXSHEETNAME
+	}
+	return name
+}
+
+func (xt *XLSXTable) getSheetXML(sheetData string, frozenRows int) string {
+	var s string
+
+	s += `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`
+
+	// freeze everything above data rows
+	s += `<sheetViews><sheetView workbookViewId="0">`
+	if frozenRows > 0 {
+		s += `<pane ySplit="` + strconv.Itoa(frozenRows) + `" topLeftCell="` + xlsxCellRef(0, frozenRows+1) + `" activePane="bottomLeft" state="frozen"/>`
+	}
+	s += `</sheetView></sheetViews>`
+
+	// column widths
+	if xt.Table.ColCount() > 0 {
+		s += `<cols>`
+		for i := 0; i < xt.Table.ColCount(); i++ {
+			n := strconv.Itoa(i + 1)
+			s += `<col min="` + n + `" max="` + n + `" width="` + strconv.Itoa(xt.Table.ColDefs[i].Width+2) + `" customWidth="1"/>`
+		}
+		s += `</cols>`
+	}
+
+	s += `<sheetData>` + sheetData + `</sheetData>`
+
+	if len(xt.merges) > 0 {
+		s += `<mergeCells count="` + strconv.Itoa(len(xt.merges)) + `">`
+		for _, m := range xt.merges {
+			s += `<mergeCell ref="` + m + `"/>`
+		}
+		s += `</mergeCells>`
+	}
+
+	return s + `</worksheet>`
+}
+
+func (xt *XLSXTable) getStylesXML() string {
+	var s string
+
+	s += `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`
+	s += `<numFmts count="` + strconv.Itoa(1+len(xt.decimals)) + `">`
+	s += `<numFmt numFmtId="` + strconv.Itoa(xlsxNumFmtDateTime) + `" formatCode="mm/dd/yyyy hh:mm:ss"/>`
+	for i, d := range xt.decimals {
+		code := "#,##0"
+		if d > 0 {
+			code += "." + strings.Repeat("0", d)
+		}
+		s += `<numFmt numFmtId="` + strconv.Itoa(xlsxNumFmtFloat+i) + `" formatCode="` + code + `"/>`
+	}
+	s += `</numFmts>`
+	s += `<fonts count="3">`
+	s += `<font><sz val="11"/><name val="Calibri"/></font>`
+	s += `<font><b/><sz val="11"/><name val="Calibri"/></font>`
+	s += `<font><b/><sz val="16"/><name val="Calibri"/></font>`
+	s += `</fonts>`
+	s += `<fills count="3">`
+	s += `<fill><patternFill patternType="none"/></fill>`
+	s += `<fill><patternFill patternType="gray125"/></fill>`
+	s += `<fill><patternFill patternType="solid"><fgColor rgb="FFDDDDDD"/><bgColor indexed="64"/></patternFill></fill>`
+	s += `</fills>`
+	s += `<borders count="4">`
+	s += `<border><left/><right/><top/><bottom/><diagonal/></border>`
+	s += `<border><left/><right/><top style="thin"/><bottom/><diagonal/></border>`
+	s += `<border><left/><right/><top/><bottom style="thin"/><diagonal/></border>`
+	s += `<border><left/><right/><top style="thin"/><bottom style="thin"/><diagonal/></border>`
+	s += `</borders>`
+	s += `<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`
+
+	s += `<cellXfs count="` + strconv.Itoa(len(xt.styles)) + `">`
+	for _, st := range xt.styles {
+		s += `<xf numFmtId="` + strconv.Itoa(st.NumFmt) + `" fontId="` + strconv.Itoa(st.Font) +
+			`" fillId="` + strconv.Itoa(st.Fill) + `" borderId="` + strconv.Itoa(st.Border) + `" xfId="0"`
+		if st.NumFmt != xlsxNumFmtGeneral {
+			s += ` applyNumberFormat="1"`
+		}
+		if st.Font != xlsxFontNormal {
+			s += ` applyFont="1"`
+		}
+		if st.Fill != xlsxFillNone {
+			s += ` applyFill="1"`
+		}
+		if st.Border != xlsxBorderNone {
+			s += ` applyBorder="1"`
+		}
+		if st.Align != "" {
+			s += ` applyAlignment="1"><alignment horizontal="` + st.Align + `"/></xf>`
+		} else {
+			s += `/>`
+		}
+	}
+	s += `</cellXfs>`
+
+	s += `<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`
+	return s + `</styleSheet>`
+}
+
+// xlsxAlign returns horizontal alignment for column justification
+func xlsxAlign(justify int) string {
+	switch justify {
+	case COLJUSTIFYLEFT:
+		return "left"
+	case COLJUSTIFYRIGHT:
+		return "right"
+	}
+	return ""
+}
+
+// xlsxCellRef returns A1 style reference for 0 based column and 1 based row
+func xlsxCellRef(col, row int) string {
+	var name string
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+// xlsxDateSerial returns the excel serial date number of the wall clock time
+// of t. The second return value is false if excel can't represent the date
+func xlsxDateSerial(t time.Time) (float64, bool) {
+	if t.Year() < 1900 || t.Year() > 9999 {
+		return 0, false
+	}
+	// the 1900 leap year bug of excel is the reason for 30th, not 31st
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := (midnight.Unix() - epoch.Unix()) / 86400
+	secs := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	return float64(days) + float64(secs)/86400, true
+}
+
+// xmlEscape returns s escaped to be used as xml text or attribute value
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// static parts of the workbook package
+const (
+	xlsxContentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+)
@@ -0,0 +1,160 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXLSX(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.SetTitle("XLSX <Table>")
+	tbl.SetSection1("Section One")
+	tbl.AddColumn("Name", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Count", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Date", 10, CELLDATE, COLJUSTIFYLEFT)
+
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "first & only")
+	tbl.Puti(-1, 1, 3)
+	tbl.Putf(-1, 2, 12345.678)
+	tbl.Putd(-1, 3, time.Date(2017, time.February, 21, 0, 0, 0, 0, time.UTC))
+	tbl.AddLineAfter(0)
+
+	var temp bytes.Buffer
+	if err := tbl.XLSXprintTable(&temp); err != nil {
+		t.Fatalf("xlsx_test: Error creating XLSX output: %s\n", err.Error())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(temp.Bytes()), int64(temp.Len()))
+	if err != nil {
+		t.Fatalf("xlsx_test: Error reading XLSX output: %s\n", err.Error())
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("xlsx_test: Error reading %s: %s\n", f.Name, err.Error())
+		}
+		b, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+
+		// every part must be well formed xml
+		d := xml.NewDecoder(bytes.NewReader(b))
+		for {
+			if _, err := d.Token(); err != nil {
+				if err != io.EOF {
+					t.Errorf("xlsx_test: %s is not well formed: %s\n", f.Name, err.Error())
+				}
+				break
+			}
+		}
+	}
+
+	sheet, ok := parts["xl/worksheets/sheet1.xml"]
+	if !ok {
+		t.Fatalf("xlsx_test: worksheet not found in %#v\n", parts)
+	}
+	var expected = []string{
+		`<mergeCell ref="A1:D1"/>`,
+		`<pane ySplit="3" topLeftCell="A4" activePane="bottomLeft" state="frozen"/>`,
+		`<v>12345.678</v>`,
+		`<v>3</v>`,
+		`<v>42787</v>`, // excel serial date for 02/21/2017
+		`first &amp; only`,
+		`<col min="1" max="1" width="22" customWidth="1"/>`,
+	}
+	for i := 0; i < len(expected); i++ {
+		if !strings.Contains(sheet, expected[i]) {
+			t.Errorf("xlsx_test: Expected %q in worksheet, but found:\n%s\n", expected[i], sheet)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="XLSX &lt;Table&gt;"`) {
+		t.Errorf("xlsx_test: Unexpected sheet name in workbook:\n%s\n", parts["xl/workbook.xml"])
+	}
+}
+
+func TestXLSXNonFinite(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	for _, f := range []float64{math.NaN(), math.Inf(1), 1.5} {
+		tbl.AddRow()
+		tbl.Putf(-1, 0, f)
+	}
+
+	sheet := xlsxParts(t, &tbl)["xl/worksheets/sheet1.xml"]
+
+	// NaN and infinity are error cells, excel rejects them as values
+	if strings.Count(sheet, `t="e"><v>#NUM!</v>`) != 2 || !strings.Contains(sheet, `<v>1.5</v>`) {
+		t.Errorf("xlsx_test: Expected error cells for NaN and infinity, found:\n%s\n", sheet)
+	}
+	if strings.Contains(sheet, "NaN") || strings.Contains(sheet, "Inf") {
+		t.Errorf("xlsx_test: Unexpected non-finite value in worksheet:\n%s\n", sheet)
+	}
+}
+
+func TestXLSXDecimals(t *testing.T) {
+	var tbl Table
+	tbl.Init() //sets column spacing and date format to default
+	tbl.AddColumn("Rate", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Units", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Price", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.ColDefs[0].Fdecimals = 4
+	tbl.ColDefs[2].Fdecimals = 0
+	tbl.AddRow()
+	for col := 0; col < 4; col++ {
+		tbl.Putf(-1, col, 1.23456)
+	}
+
+	// one number format per number of decimals
+	styles := xlsxParts(t, &tbl)["xl/styles.xml"]
+	for _, e := range []string{
+		`<numFmts count="4">`,
+		`<numFmt numFmtId="165" formatCode="#,##0.0000"/>`,
+		`<numFmt numFmtId="166" formatCode="#,##0.00"/>`,
+		`<numFmt numFmtId="167" formatCode="#,##0"/>`,
+		`<xf numFmtId="165" `,
+		`<xf numFmtId="167" `,
+	} {
+		if !strings.Contains(styles, e) {
+			t.Errorf("xlsx_test: Expected %q in styles, but found:\n%s\n", e, styles)
+		}
+	}
+	if strings.Count(styles, `<numFmt `) != 4 {
+		t.Errorf("xlsx_test: Expected 4 number formats, found:\n%s\n", styles)
+	}
+}
+
+// xlsxParts returns the parts of the xlsx output of tbl by name
+func xlsxParts(t *testing.T, tbl *Table) map[string]string {
+	var temp bytes.Buffer
+	if err := tbl.XLSXprintTable(&temp); err != nil {
+		t.Fatalf("xlsx_test: Error creating XLSX output: %s\n", err.Error())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(temp.Bytes()), int64(temp.Len()))
+	if err != nil {
+		t.Fatalf("xlsx_test: Error reading XLSX output: %s\n", err.Error())
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("xlsx_test: Error reading %s: %s\n", f.Name, err.Error())
+		}
+		b, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	return parts
+}